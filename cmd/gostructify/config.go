@@ -0,0 +1,500 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/snagles/gostructify"
+	"github.com/snagles/gostructify/database"
+	"github.com/snagles/gostructify/structify"
+	"github.com/urfave/cli"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Config is a project configuration file describing every file to generate, ex:
+//
+//	connections:
+//	  app:
+//	    driver: postgresql
+//	    hostname: 127.0.0.1
+//	    username: app
+//	    password_env: APP_DB_PASSWORD
+//	tags: [json, sqlx]
+//	methods: [sqlx]
+//	targets:
+//	- connection: app
+//	  database: app
+//	  tables: ["*"]
+//	  exclude_tables: ["^schema_migrations$"]
+//	  output: models/app_gostructify.go
+type Config struct {
+	Connections map[string]Connection `yaml:"connections"`
+	Targets     []Target              `yaml:"targets"`
+	// Types are the type overrides of every target
+	Types   database.TypeOverrides `yaml:"types"`
+	Options `yaml:",inline"`
+
+	dir string // directory of the configuration file, relative paths are resolved against it
+}
+
+// Connection describes how to reach a database, the fields used depend on the driver. Database
+// servers can be reached with a url, or the hostname and port or socket, ex:
+//
+//	url: postgres://app@db.example.com/app?sslmode=verify-full
+//	sslrootcert: certs/ca.pem
+//	connect_timeout: 10s
+//	params:
+//	  application_name: gostructify
+type Connection struct {
+	Driver   string `yaml:"driver"`
+	URL      string `yaml:"url"`
+	Hostname string `yaml:"hostname"`
+	Port     int    `yaml:"port"`
+	Socket   string `yaml:"socket"`
+	Username string `yaml:"username"`
+	// Password is rejected when the configuration is loaded, configuration files are committed with
+	// the project so the password is read from PasswordEnv or PasswordCommand
+	Password string `yaml:"password"`
+	// SSLMode is disable, require, verify-ca or verify-full, certificate files are relative to the
+	// configuration file
+	SSLMode        string            `yaml:"sslmode"`
+	SSLRootCert    string            `yaml:"sslrootcert"`
+	SSLCert        string            `yaml:"sslcert"`
+	SSLKey         string            `yaml:"sslkey"`
+	ConnectTimeout time.Duration     `yaml:"connect_timeout"`
+	Params         map[string]string `yaml:"params"`
+	// PasswordEnv is the environment variable holding the password, PasswordCommand is a shell
+	// command printing it
+	PasswordEnv     string `yaml:"password_env"`
+	PasswordCommand string `yaml:"password_command"`
+	DSN             string `yaml:"dsn"`
+	// ODBC connects to vertica with the odbc driver instead of the native driver
+	ODBC   bool   `yaml:"odbc"`
+	Schema string `yaml:"schema"`
+	// Schemas are the postgresql schemas tables are listed from, defaults to the search_path
+	Schemas []string `yaml:"schemas"`
+	Path    string   `yaml:"path"`
+	Dialect string   `yaml:"dialect"`
+	Files   []string `yaml:"files"`
+	Dir     string   `yaml:"dir"`
+}
+
+// Target is an output file generated from the tables of a database of a connection. The options of
+// a target override the options set at the top of the configuration file.
+type Target struct {
+	Name          string   `yaml:"name"`
+	Connection    string   `yaml:"connection"`
+	Database      string   `yaml:"database"`
+	Tables        []string `yaml:"tables"`
+	ExcludeTables []string `yaml:"exclude_tables"`
+	Output        string   `yaml:"output"`
+	// Package defaults to the package of the go files of the output directory, or its name
+	Package string `yaml:"package"`
+	// SaveSnapshot is the json or yaml file the tables of the target are saved to
+	SaveSnapshot string `yaml:"save_snapshot"`
+	Options      `yaml:",inline"`
+}
+
+// Options are the generation options shared by the configuration file and its targets,
+// unset options are inherited
+type Options struct {
+	Tags         []string `yaml:"tags"`
+	Methods      []string `yaml:"methods"`
+	NullableType string   `yaml:"nullable_type"`
+	Precise      *bool    `yaml:"precise"`
+	Decimal      string   `yaml:"decimal"`
+	Relations    *bool    `yaml:"relations"`
+	Views        *bool    `yaml:"views"`
+	Naming       Naming   `yaml:"naming"`
+	// Templates are text/template files, relative to the configuration file
+	Templates []string `yaml:"templates"`
+}
+
+// Naming are the rules converting table and column names to struct and field names, ex:
+//
+//	naming:
+//	  trim_prefix: tbl_
+//	  singular: true
+//	  tables:
+//	    tbl_usr: User
+//	  columns:
+//	    usr_nm: Name
+//	    users.created_ts: CreatedAt
+//	  initialisms: [SKU]
+type Naming struct {
+	TrimPrefix string `yaml:"trim_prefix"`
+	TrimSuffix string `yaml:"trim_suffix"`
+	Singular   *bool  `yaml:"singular"`
+	// QualifySchemas are the schemas whose struct names are prefixed with the schema, * for every schema
+	QualifySchemas []string `yaml:"qualify_schemas"`
+	// Tables and Columns rename tables and columns, columns are named column, table.column or schema.table.column
+	Tables        map[string]string `yaml:"tables"`
+	Columns       map[string]string `yaml:"columns"`
+	Initialisms   []string          `yaml:"initialisms"`
+	NoInitialisms *bool             `yaml:"no_initialisms"`
+}
+
+// LoadConfig reads and validates a project configuration file
+func LoadConfig(file string) (*Config, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(b, &config); err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	if err := config.Types.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	for name, conn := range config.Connections {
+		if conn.Password != "" {
+			return nil, fmt.Errorf("%s: connection %s has a password, set password_env or password_command instead", file, name)
+		}
+	}
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("%s: no targets to generate", file)
+	}
+	for i, t := range config.Targets {
+		if _, ok := config.Connections[t.Connection]; !ok {
+			return nil, fmt.Errorf("%s: target %s uses unknown connection %q", file, t.name(i), t.Connection)
+		}
+		if t.Output == "" {
+			return nil, fmt.Errorf("%s: target %s is missing an output file", file, t.name(i))
+		}
+		if len(t.Tables) == 0 {
+			return nil, fmt.Errorf("%s: target %s has no tables", file, t.name(i))
+		}
+	}
+	config.dir = filepath.Dir(file)
+	return &config, nil
+}
+
+// name returns the name of the target, or its position when it is not named
+func (t Target) name(i int) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return fmt.Sprintf("%d", i+1)
+}
+
+// path resolves a path of the configuration file against the directory of the file
+func (config *Config) path(name string) string {
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(config.dir, name)
+}
+
+// paths resolves the paths of the configuration file against the directory of the file
+func (config *Config) paths(names []string) []string {
+	var paths []string
+	for _, name := range names {
+		paths = append(paths, config.path(name))
+	}
+	return paths
+}
+
+// target returns the output file of the configured target with the inherited options resolved, file
+// is the configuration file recorded in the code generated header
+func (config *Config) target(t Target, file string, dryRun, check bool) (target, error) {
+	o := t.Options.inherit(config.Options)
+	if t.Database == "" {
+		// the database of a connection url is the default database of its targets
+		t.Database = database.Connection{URL: config.Connections[t.Connection].URL}.URLDatabase()
+	}
+	output := config.path(t.Output)
+	pkg, err := packageName(t.Package, filepath.Dir(output))
+	if err != nil {
+		return target{}, err
+	}
+	return target{
+		Options: gostructify.Options{
+			Database:      t.Database,
+			Tables:        t.Tables,
+			ExcludeTables: t.ExcludeTables,
+			Views:         o.Views != nil && *o.Views,
+			Precise:       o.Precise != nil && *o.Precise,
+			Decimal:       o.Decimal,
+			Types:         config.Types,
+			Struct: structify.Options{
+				Tags:         o.Tags,
+				Methods:      o.Methods,
+				NullableType: o.NullableType,
+				Relations:    o.Relations != nil && *o.Relations,
+				Naming: structify.Naming{
+					TrimPrefix:     o.Naming.TrimPrefix,
+					TrimSuffix:     o.Naming.TrimSuffix,
+					Singular:       o.Naming.Singular != nil && *o.Naming.Singular,
+					QualifySchemas: o.Naming.QualifySchemas,
+					Tables:         o.Naming.Tables,
+					Columns:        o.Naming.Columns,
+					Initialisms:    o.Naming.Initialisms,
+					NoInitialisms:  o.Naming.NoInitialisms != nil && *o.Naming.NoInitialisms,
+				},
+				Templates: config.paths(o.Templates),
+			},
+			Package: pkg,
+			Output:  output,
+			// the configuration file relative to the output, so the header is the same on every machine
+			Command: "gostructify generate -c " + shellQuote(relativePath(filepath.Dir(output), file)),
+		},
+		dryRun: dryRun,
+		check:  check,
+	}, nil
+}
+
+// inherit returns the options with the unset options taken from the parent options
+func (o Options) inherit(parent Options) Options {
+	if o.Tags == nil {
+		o.Tags = parent.Tags
+	}
+	if o.Methods == nil {
+		o.Methods = parent.Methods
+	}
+	if o.NullableType == "" {
+		o.NullableType = parent.NullableType
+	}
+	if o.Precise == nil {
+		o.Precise = parent.Precise
+	}
+	if o.Decimal == "" {
+		o.Decimal = parent.Decimal
+	}
+	if o.Relations == nil {
+		o.Relations = parent.Relations
+	}
+	if o.Views == nil {
+		o.Views = parent.Views
+	}
+	o.Naming = o.Naming.inherit(parent.Naming)
+	if o.Templates == nil {
+		o.Templates = parent.Templates
+	}
+	return o
+}
+
+// inherit returns the naming rules with the unset rules taken from the parent rules
+func (n Naming) inherit(parent Naming) Naming {
+	if n.TrimPrefix == "" {
+		n.TrimPrefix = parent.TrimPrefix
+	}
+	if n.TrimSuffix == "" {
+		n.TrimSuffix = parent.TrimSuffix
+	}
+	if n.Singular == nil {
+		n.Singular = parent.Singular
+	}
+	if n.QualifySchemas == nil {
+		n.QualifySchemas = parent.QualifySchemas
+	}
+	if n.Tables == nil {
+		n.Tables = parent.Tables
+	}
+	if n.Columns == nil {
+		n.Columns = parent.Columns
+	}
+	if n.Initialisms == nil {
+		n.Initialisms = parent.Initialisms
+	}
+	if n.NoInitialisms == nil {
+		n.NoInitialisms = parent.NoInitialisms
+	}
+	return n
+}
+
+// packageName returns the configured package name, the package of the go files of the output
+// directory, or the name of the output directory
+func packageName(name, dir string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if pkg, err := build.Default.ImportDir(dir, 0); err == nil {
+		return pkg.Name, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving package directory %s: %s", dir, err)
+	}
+	return strings.Replace(filepath.Base(abs), "-", "_", -1), nil
+}
+
+// open returns the database of the connection, databases are those of the targets it is opened for
+func (config *Config) open(c *cli.Context, conn Connection, databases []string) (database.Database, error) {
+	var server database.Connection
+	switch conn.Driver {
+	case "mariadb", "mysql", "postgresql", "mssql", "vertica":
+		var err error
+		if server, err = config.connection(c, conn, databases); err != nil {
+			return nil, err
+		}
+	}
+	switch conn.Driver {
+	case "mariadb":
+		return database.MariaDB{Connection: server}, nil
+	case "mysql":
+		return database.MySQL{Connection: server}, nil
+	case "postgresql":
+		schemas := conn.Schemas
+		if conn.Schema != "" {
+			schemas = append([]string{conn.Schema}, schemas...)
+		}
+		return database.PostgreSQL{Connection: server, Schemas: schemas}, nil
+	case "vertica":
+		return database.Vertica{DSN: conn.DSN, ODBC: conn.ODBC, Connection: server}, nil
+	case "mssql":
+		schema := conn.Schema
+		if schema == "" {
+			schema = "dbo"
+		}
+		return database.MSSQL{Connection: server, Schema: schema}, nil
+	case "sqlite":
+		return database.SQLite{Path: config.path(conn.Path)}, nil
+	case "ddl":
+		s, err := database.NewSchema(conn.Dialect)
+		if err != nil {
+			return nil, err
+		}
+		files, err := sqlFiles(strings.Join(config.paths(conn.Files), ","))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if err := s.ApplyFile(f); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	case "migrations":
+		return database.LoadMigrations(conn.Dialect, config.path(conn.Dir))
+	case "snapshot":
+		return database.LoadSnapshot(config.path(conn.Path))
+	}
+	return nil, fmt.Errorf("unrecognized driver %q, expected one of mariadb, mysql, postgresql, vertica, mssql, sqlite, ddl, migrations, snapshot", conn.Driver)
+}
+
+// connection returns the options of the connection to a database server, certificate paths are
+// relative to the configuration file. The password is not needed with an odbc data source.
+func (config *Config) connection(c *cli.Context, conn Connection, databases []string) (database.Connection, error) {
+	server := database.Connection{
+		URL:            conn.URL,
+		Hostname:       conn.Hostname,
+		Port:           conn.Port,
+		Socket:         conn.Socket,
+		Username:       conn.Username,
+		SSLMode:        conn.SSLMode,
+		SSLRootCert:    config.path(conn.SSLRootCert),
+		SSLCert:        config.path(conn.SSLCert),
+		SSLKey:         config.path(conn.SSLKey),
+		ConnectTimeout: conn.ConnectTimeout,
+		Params:         conn.Params,
+		Warn:           logWarning,
+	}
+	if err := server.Validate(); err != nil {
+		return server, err
+	}
+	if server.HasPassword() || conn.DSN != "" {
+		return server, nil
+	}
+	var err error
+	server.Password, err = conn.password(c, server, databases)
+	return server, err
+}
+
+// password returns the password of the connection, read from its environment variable or password
+// command when set, otherwise the password of the command line options is used
+func (conn Connection) password(c *cli.Context, server database.Connection, databases []string) (string, error) {
+	if conn.PasswordEnv != "" {
+		if password, ok := os.LookupEnv(conn.PasswordEnv); ok {
+			return password, nil
+		}
+		return "", fmt.Errorf("Missing password. The environment variable %s of the connection is not set", conn.PasswordEnv)
+	}
+	if conn.PasswordCommand != "" {
+		return passwordCommand(conn.PasswordCommand)
+	}
+	return getPassword(c, conn.Driver, server, databases)
+}
+
+// databases returns the databases of the selected targets of the connection
+func (config *Config) databases(connection string, selected map[string]bool) []string {
+	var databases []string
+	for _, t := range config.Targets {
+		if t.Connection != connection || (len(selected) > 0 && !selected[t.Name]) {
+			continue
+		}
+		db := t.Database
+		if db == "" {
+			db = database.Connection{URL: config.Connections[connection].URL}.URLDatabase()
+		}
+		databases = append(databases, db)
+	}
+	return databases
+}
+
+// generateConfig generates, or checks, every target of the configuration file, or the targets named
+// by --target. Connections are opened once and shared by their targets.
+func generateConfig(c *cli.Context, check bool) error {
+	config, err := LoadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("Failed to read config: %s", err)
+	}
+	names := map[string]bool{}
+	for _, t := range config.Targets {
+		names[t.Name] = true
+	}
+	selected := map[string]bool{}
+	for _, name := range c.StringSlice("target") {
+		if !names[name] {
+			return fmt.Errorf("Unknown target %s", name)
+		}
+		selected[name] = true
+	}
+	opened := map[string]database.Database{}
+	snapshots := map[string]*database.Snapshot{}
+	for i, t := range config.Targets {
+		if len(selected) > 0 && !selected[t.Name] {
+			continue
+		}
+		d, ok := opened[t.Connection]
+		if !ok {
+			// no connection is made when generating from a snapshot
+			if d, err = fromSnapshot(nil, c); err != nil {
+				return err
+			}
+			if d == nil {
+				if d, err = config.open(c, config.Connections[t.Connection], config.databases(t.Connection, selected)); err != nil {
+					return fmt.Errorf("Failed to open connection %s of target %s: %w", t.Connection, t.name(i), err)
+				}
+			}
+			opened[t.Connection] = d
+		}
+		target, err := config.target(t, c.String("config"), c.GlobalBool("dry-run"), check)
+		if err != nil {
+			return err
+		}
+		// snapshots are not saved when checking or when the tables come from a snapshot
+		if file := config.path(t.SaveSnapshot); file != "" && !check && c.GlobalString("from-snapshot") == "" {
+			if snapshots[file] == nil {
+				if snapshots[file], err = openSnapshot(file); err != nil {
+					return err
+				}
+			}
+			target.Snapshot = snapshots[file]
+		}
+		if err := generate(d, target); err != nil {
+			return err
+		}
+	}
+	for file, s := range snapshots {
+		if err := saveSnapshot(s, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
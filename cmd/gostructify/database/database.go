@@ -1,5 +0,0 @@
-package database
-
-type Database interface {
-	Build(string, string) (*Table, error)
-}
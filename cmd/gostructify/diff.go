@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffMaxEdits bounds the edits of the forward and backward paths of the diff, lines that differ more
+// are diffed by removing all of them and adding the new ones
+const diffMaxEdits = 1000
+
+// edit is a line of either file prefixed by ' ' when it is in both, '-' when it is removed or '+' when
+// it is added
+type edit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the unified diff of the lines of a and b, empty when they are equal
+func unifiedDiff(nameA, nameB string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", nameA, nameB)
+	for start := 0; start < len(edits); {
+		// find the next change and the end of its hunk, changes closer than twice the
+		// context are joined in one hunk
+		first := start
+		for first < len(edits) && edits[first].op == ' ' {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		last := first
+		for k := first; k < len(edits) && k-last <= 2*diffContext; k++ {
+			if edits[k].op != ' ' {
+				last = k
+			}
+		}
+		from, to := first-diffContext, last+diffContext+1
+		if from < start {
+			from = start
+		}
+		if to > len(edits) {
+			to = len(edits)
+		}
+
+		// line numbers of the hunk in both files
+		lineA, lineB := 1, 1
+		for _, e := range edits[:from] {
+			if e.op != '+' {
+				lineA++
+			}
+			if e.op != '-' {
+				lineB++
+			}
+		}
+		countA, countB := 0, 0
+		for _, e := range edits[from:to] {
+			if e.op != '+' {
+				countA++
+			}
+			if e.op != '-' {
+				countB++
+			}
+		}
+		if countA == 0 {
+			lineA--
+		}
+		if countB == 0 {
+			lineB--
+		}
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", lineA, countA, lineB, countB)
+		for _, e := range edits[from:to] {
+			fmt.Fprintf(&buf, "%c%s\n", e.op, e.line)
+		}
+		start = to
+	}
+	return buf.String()
+}
+
+// splitLines splits the text into lines without their line endings
+func splitLines(text []byte) []string {
+	if len(text) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+}
+
+// diffLines returns the edits turning the lines of x into the lines of y. The common prefix and suffix
+// are kept and the lines between are split at a middle snake of the Myers diff, so the space used is
+// linear in the number of lines.
+func diffLines(x, y []string) []edit {
+	prefix, suffix := 0, 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	var edits []edit
+	for _, line := range x[:prefix] {
+		edits = append(edits, edit{' ', line})
+	}
+	a, b := x[prefix:len(x)-suffix], y[prefix:len(y)-suffix]
+	if i, j, ok := middleSnake(a, b); ok {
+		edits = append(edits, diffLines(a[:i], b[:j])...)
+		edits = append(edits, diffLines(a[i:], b[j:])...)
+	} else {
+		for _, line := range a {
+			edits = append(edits, edit{'-', line})
+		}
+		for _, line := range b {
+			edits = append(edits, edit{'+', line})
+		}
+	}
+	for _, line := range x[len(x)-suffix:] {
+		edits = append(edits, edit{' ', line})
+	}
+	return edits
+}
+
+// middleSnake returns the point splitting the shortest edit script of a and b in two smaller scripts,
+// found by running the Myers diff forward from the start and backward from the end until the paths
+// overlap. It reports false when one of them is empty, when they have nothing in common or when the
+// paths do not meet within diffMaxEdits edits.
+func middleSnake(a, b []string) (int, int, bool) {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+	maxD := (n + m + 1) / 2
+	if maxD > diffMaxEdits {
+		maxD = diffMaxEdits
+	}
+	offset := maxD
+	// forward[offset+k] is the furthest x of the forward path on diagonal k = x - y, backward[offset+k]
+	// the furthest x of the backward path on diagonal k counted from the end of both files
+	forward, backward := make([]int, 2*maxD+2), make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	// the paths meet in the forward pass when the difference of the lengths is odd
+	odd := delta%2 != 0
+	// the diagonals leaving the edit graph are not extended any further
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k := -d + forwardStart; k <= d-forwardEnd; k += 2 {
+			var x int
+			if k == -d || k != d && forward[offset+k-1] < forward[offset+k+1] {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case odd:
+				if i := offset + delta - k; i >= 0 && i < len(backward) && backward[i] != -1 && x >= n-backward[i] {
+					return split(x, y, n, m)
+				}
+			}
+		}
+		for k := -d + backwardStart; k <= d-backwardEnd; k += 2 {
+			var x int
+			if k == -d || k != d && backward[offset+k-1] < backward[offset+k+1] {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !odd:
+				if i := offset + delta - k; i >= 0 && i < len(forward) && forward[i] != -1 && forward[i] >= n-x {
+					return split(forward[i], forward[i]-(delta-k), n, m)
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// split returns the split point of the edit graph of n by m lines, it is refused when it leaves the
+// whole graph on one side
+func split(x, y, n, m int) (int, int, bool) {
+	if x+y == 0 || x == n && y == m {
+		return 0, 0, false
+	}
+	return x, y, true
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// generatePrefix marks a go generate comment, ex: //go:generate gostructify --tags json mariadb --tables users
+	generatePrefix = "//go:generate"
+	// markerPrefix marks a table declaration that is only picked up by the scan command,
+	// ex: //gostructify:table --tags json mariadb --tables users
+	markerPrefix = "//gostructify:table"
+)
+
+// Directive is a single gostructify invocation declared in a comment of a package file.
+type Directive struct {
+	File string   // File the directive was declared in.
+	Line int      // Line of the directive within the file.
+	Args []string // Command line arguments following the gostructify command name.
+}
+
+func (d Directive) String() string {
+	return fmt.Sprintf("%s:%d", d.File, d.Line)
+}
+
+// directives returns every gostructify directive declared in the parsed package files
+// in the order they appear.
+func (g *Generator) directives() ([]Directive, error) {
+	var ds []Directive
+	for _, f := range g.pkg.files {
+		for _, group := range f.file.Comments {
+			for _, comment := range group.List {
+				pos := g.pkg.fset.Position(comment.Slash)
+				args, ok, err := parseDirective(comment.Text, pos.Filename, pos.Line, g.pkg.name)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %s", pos.Filename, pos.Line, err)
+				}
+				if !ok {
+					continue
+				}
+				ds = append(ds, Directive{File: pos.Filename, Line: pos.Line, Args: args})
+			}
+		}
+	}
+	return ds, nil
+}
+
+// parseDirective reports whether the comment text is a gostructify directive and returns
+// its arguments. Arguments are split and expanded the same way go generate does.
+func parseDirective(text, file string, line int, pkg string) ([]string, bool, error) {
+	var rest string
+	var marker bool
+	switch {
+	case strings.HasPrefix(text, generatePrefix+" "):
+		rest = strings.TrimPrefix(text, generatePrefix)
+	case strings.HasPrefix(text, markerPrefix+" "):
+		rest = strings.TrimPrefix(text, markerPrefix)
+		marker = true
+	default:
+		return nil, false, nil
+	}
+
+	words, err := splitArgs(rest, file, line, pkg)
+	if err != nil {
+		return nil, false, err
+	}
+	if !marker {
+		// only go generate commands running gostructify are of interest,
+		// either as an installed binary or through go run
+		switch {
+		case len(words) > 0 && isGostructify(words[0]):
+			words = words[1:]
+		case len(words) > 2 && words[0] == "go" && words[1] == "run" && isGostructify(words[2]):
+			words = words[3:]
+		default:
+			return nil, false, nil
+		}
+	}
+	if len(words) == 0 {
+		return nil, false, fmt.Errorf("directive is missing arguments")
+	}
+	// a scan directive would scan itself
+	for _, w := range words {
+		if w == "scan" {
+			return nil, false, nil
+		}
+	}
+	return words, true, nil
+}
+
+func isGostructify(command string) bool {
+	return strings.TrimSuffix(filepath.Base(command), ".exe") == "gostructify"
+}
+
+// splitArgs splits the text into space separated words, honoring double quoted
+// strings and expanding environment variables like go generate.
+func splitArgs(text, file string, line int, pkg string) ([]string, error) {
+	var words []string
+	text = strings.TrimSpace(text)
+	for text != "" {
+		if text[0] == '"' {
+			// quoted strings follow go syntax
+			end := 1
+			for ; end < len(text); end++ {
+				if text[end] == '\\' {
+					end++
+					continue
+				}
+				if text[end] == '"' {
+					break
+				}
+			}
+			if end >= len(text) {
+				return nil, fmt.Errorf("unterminated quoted string")
+			}
+			word, err := strconv.Unquote(text[:end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted string: %s", err)
+			}
+			words = append(words, expandVar(word, file, line, pkg))
+			text = strings.TrimSpace(text[end+1:])
+			continue
+		}
+		end := strings.IndexAny(text, " \t")
+		if end < 0 {
+			end = len(text)
+		}
+		words = append(words, expandVar(text[:end], file, line, pkg))
+		text = strings.TrimSpace(text[end:])
+	}
+	return words, nil
+}
+
+// expandVar expands the variables go generate makes available to its commands.
+func expandVar(word, file string, line int, pkg string) string {
+	return os.Expand(word, func(name string) string {
+		switch name {
+		case "GOFILE":
+			return filepath.Base(file)
+		case "GOLINE":
+			return strconv.Itoa(line)
+		case "GOPACKAGE":
+			return pkg
+		case "GOARCH":
+			return runtime.GOARCH
+		case "GOOS":
+			return runtime.GOOS
+		case "DOLLAR":
+			return "$"
+		}
+		return os.Getenv(name)
+	})
+}
+
+// mergeDirectives combines directives that only differ by their --tables value so
+// that each output file is generated once with the union of the declared tables.
+func mergeDirectives(ds []Directive) []Directive {
+	var merged []Directive
+	index := map[string]int{}
+	for _, d := range ds {
+		tables, args := removeFlag(d.Args, "tables")
+		key := strings.Join(args, "\x00")
+		i, ok := index[key]
+		if !ok || tables == "" {
+			if tables != "" {
+				index[key] = len(merged)
+			}
+			merged = append(merged, d)
+			continue
+		}
+		existing, args := removeFlag(merged[i].Args, "tables")
+		merged[i].Args = append(args, "--tables", unionList(existing, tables))
+	}
+	return merged
+}
+
+// removeFlag returns the value of the named flag and the arguments without it.
+func removeFlag(args []string, name string) (string, []string) {
+	var value string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-"+name || a == "--"+name:
+			if i+1 < len(args) {
+				value = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(a, "-"+name+"=") || strings.HasPrefix(a, "--"+name+"="):
+			value = a[strings.Index(a, "=")+1:]
+		default:
+			rest = append(rest, a)
+		}
+	}
+	return value, rest
+}
+
+// unionList joins two comma separated lists without repeating entries.
+func unionList(a, b string) string {
+	var out []string
+	seen := map[string]bool{}
+	for _, v := range strings.Split(a+","+b, ",") {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return strings.Join(out, ",")
+}
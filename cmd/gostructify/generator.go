@@ -1,41 +1,36 @@
 package main
 
 import (
+	"fmt"
 	"go/ast"
 	"go/build"
 	"go/parser"
 	"go/token"
 	"go/types"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
 // isDirectory reports whether the named file is a directory.
-func isDirectory(name string) bool {
+func isDirectory(name string) (bool, error) {
 	info, err := os.Stat(name)
 	if err != nil {
-		log.Fatal(err)
+		return false, err
 	}
-	return info.IsDir()
+	return info.IsDir(), nil
 }
 
 // Generator holds the state of the analysis. Primarily used to buffer
 // the output for format.Source.
 type Generator struct {
-	pkg         *Package // Package we are scanning.
-	trimPrefix  string
-	lineComment bool
+	pkg *Package // Package we are scanning.
 }
 
 // File holds a single parsed file and associated data.
 type File struct {
 	pkg  *Package  // Package to which this file belongs.
 	file *ast.File // Parsed AST.
-	// These fields are reset for each type being generated.
-	trimPrefix  string
-	lineComment bool
 }
 
 // Package represents the parses package
@@ -44,14 +39,15 @@ type Package struct {
 	name     string
 	defs     map[*ast.Ident]types.Object
 	files    []*File
+	fset     *token.FileSet
 	typesPkg *types.Package
 }
 
 // parsePackageDir parses the package residing in the directory.
-func (g *Generator) parsePackageDir(directory string) {
+func (g *Generator) parsePackageDir(directory string) error {
 	pkg, err := build.Default.ImportDir(directory, 0)
 	if err != nil {
-		log.Fatalf("cannot process directory %s: %s", directory, err)
+		return fmt.Errorf("cannot process directory %s: %s", directory, err)
 	}
 	var names []string
 	names = append(names, pkg.GoFiles...)
@@ -61,12 +57,12 @@ func (g *Generator) parsePackageDir(directory string) {
 	// names = append(names, pkg.TestGoFiles...) // These are also in the "foo" package.
 	names = append(names, pkg.SFiles...)
 	names = prefixDirectory(directory, names)
-	g.parsePackage(directory, names, nil)
+	return g.parsePackage(directory, names, nil)
 }
 
 // parsePackageFiles parses the package occupying the named files.
-func (g *Generator) parsePackageFiles(names []string) {
-	g.parsePackage(".", names, nil)
+func (g *Generator) parsePackageFiles(names []string) error {
+	return g.parsePackage(".", names, nil)
 }
 
 // prefixDirectory places the directory name on the beginning of each name in the list.
@@ -83,8 +79,8 @@ func prefixDirectory(directory string, names []string) []string {
 
 // parsePackage analyzes the single package constructed from the named files.
 // If text is non-nil, it is a string to be used instead of the content of the file,
-// to be used for testing. parsePackage exits if there is an error.
-func (g *Generator) parsePackage(directory string, names []string, text interface{}) {
+// to be used for testing.
+func (g *Generator) parsePackage(directory string, names []string, text interface{}) error {
 	var files []*File
 	var astFiles []*ast.File
 	g.pkg = new(Package)
@@ -95,20 +91,20 @@ func (g *Generator) parsePackage(directory string, names []string, text interfac
 		}
 		parsedFile, err := parser.ParseFile(fs, name, text, parser.ParseComments)
 		if err != nil {
-			log.Fatalf("parsing package: %s: %s", name, err)
+			return fmt.Errorf("parsing package: %s: %s", name, err)
 		}
 		astFiles = append(astFiles, parsedFile)
 		files = append(files, &File{
-			file:        parsedFile,
-			pkg:         g.pkg,
-			trimPrefix:  g.trimPrefix,
-			lineComment: g.lineComment,
+			file: parsedFile,
+			pkg:  g.pkg,
 		})
 	}
 	if len(astFiles) == 0 {
-		log.Fatalf("%s: no buildable Go files", directory)
+		return fmt.Errorf("%s: no buildable Go files", directory)
 	}
 	g.pkg.name = astFiles[0].Name.Name
 	g.pkg.files = files
+	g.pkg.fset = fs
 	g.pkg.dir = directory
+	return nil
 }
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// headerFlags are the flags recorded in the code generated header, the options that change the
+// generated source. Connection and credential flags are never recorded so the header holds no secret
+// and does not depend on how a developer reaches the database.
+var headerFlags = map[string]bool{
+	// global flags
+	"nullabletype": true, "tags": true, "methods": true, "template": true, "types": true, "precise": true,
+	"decimal": true, "trim-prefix": true, "trim-suffix": true, "qualify-schemas": true, "singular": true,
+	"rename-table": true, "rename-column": true, "initialisms": true, "no-initialisms": true,
+	"relations": true, "views": true,
+	// command flags
+	"database": true, "schema": true, "tables": true, "exclude-tables": true, "path": true, "dialect": true,
+	"files": true, "dir": true,
+}
+
+// pathFlags are the flags holding comma separated paths, recorded relative to the output directory
+var pathFlags = map[string]bool{"template": true, "types": true, "path": true, "files": true, "dir": true}
+
+// headerCommand returns the command recorded in the code generated header of the output file of the
+// database. It holds the header flags that are set in the order they are declared, with the database
+// of the file and paths relative to the output directory, so the header is the same on every machine.
+func headerCommand(c *cli.Context, db, output string) string {
+	dir := filepath.Dir(output)
+	args := []string{"gostructify"}
+	args = append(args, headerArgs(setFlags(c, c.App.Flags, true), dir)...)
+	args = append(args, c.Command.Name)
+	flags := setFlags(c, c.Command.Flags, false)
+	hasDatabase := false
+	for i, f := range flags {
+		if f.name == "database" {
+			flags[i].values, hasDatabase = []string{db}, true
+		}
+	}
+	if !hasDatabase && db != "" {
+		// the database of a connection url
+		flags = append(flags, flagValue{name: "database", values: []string{db}})
+	}
+	args = append(args, headerArgs(flags, dir)...)
+	for i, arg := range args {
+		args[i] = shellQuote(arg)
+	}
+	return strings.Join(args, " ")
+}
+
+// headerArgs returns the arguments of the header flags with paths relative to the directory
+func headerArgs(flags []flagValue, dir string) []string {
+	var args []string
+	for _, f := range flags {
+		if headerFlags[f.name] {
+			args = append(args, f.relative(dir).args()...)
+		}
+	}
+	return args
+}
+
+// relativePath returns the path relative to the directory with forward slashes, the path as given when
+// it can not be made relative
+func relativePath(dir, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// flagValue is a flag set on the command line, bool flags have no value and slice flags have a value
+// for each time they are set
+type flagValue struct {
+	name    string
+	values  []string
+	boolean bool
+}
+
+// setFlags returns the flags that are set in the order they are declared, global flags are read from
+// the global options
+func setFlags(c *cli.Context, flags []cli.Flag, global bool) []flagValue {
+	var set []flagValue
+	for _, f := range flags {
+		name := strings.Split(f.GetName(), ",")[0]
+		if global && !c.GlobalIsSet(name) || !global && !c.IsSet(name) {
+			continue
+		}
+		v := flagValue{name: name}
+		switch f.(type) {
+		case cli.BoolFlag:
+			if global && !c.GlobalBool(name) || !global && !c.Bool(name) {
+				continue
+			}
+			v.boolean = true
+		case cli.StringSliceFlag:
+			if global {
+				v.values = c.GlobalStringSlice(name)
+			} else {
+				v.values = c.StringSlice(name)
+			}
+		default:
+			if global {
+				v.values = []string{c.GlobalString(name)}
+			} else {
+				v.values = []string{c.String(name)}
+			}
+		}
+		set = append(set, v)
+	}
+	return set
+}
+
+// args returns the command line arguments of the flag
+func (f flagValue) args() []string {
+	if f.boolean {
+		return []string{"--" + f.name}
+	}
+	var args []string
+	for _, value := range f.values {
+		args = append(args, "--"+f.name, value)
+	}
+	return args
+}
+
+// relative returns the flag with its paths relative to the directory when it is a path flag
+func (f flagValue) relative(dir string) flagValue {
+	if !pathFlags[f.name] {
+		return f
+	}
+	values := make([]string, len(f.values))
+	for i, value := range f.values {
+		paths := splitList(value)
+		for j, path := range paths {
+			paths[j] = relativePath(dir, path)
+		}
+		values[i] = strings.Join(paths, ",")
+	}
+	f.values = values
+	return f
+}
+
+// plainArg matches the arguments that need no quoting in a shell
+var plainArg = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes the argument with single quotes when a shell would interpret it
+func shellQuote(arg string) string {
+	if plainArg.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
@@ -9,24 +9,41 @@
 
 // Typically this process would be run using go generate, like this:
 //
-// You will then be prompted for host connection details:
+//	//go:generate gostructify --tags json,gorm mariadb --hostname 127.0.0.1 --username root --database test --tables users
+//
+// You will then be prompted for host connection details unless they are passed as options.
+// Tables can also be declared with a marker comment that go generate ignores:
+//
+//	//gostructify:table --tags json,gorm mariadb --hostname 127.0.0.1 --username root --database test --tables admins
+//
+// Every directive of a package, or of a tree of packages, is then regenerated in one pass with:
+//
+//	gostructify scan ./...
+//
+// Directives that only differ by their tables are merged so each output file is written once.
+//
 // TODO expand usage details
 package main
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"go/token"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/howeyc/gopass"
 	"github.com/sirupsen/logrus"
-	"github.com/snagles/gostructify/cmd/gostructify/database"
-	"github.com/snagles/gostructify/cmd/gostructify/structify"
+	"github.com/snagles/gostructify"
+	"github.com/snagles/gostructify/database"
+	"github.com/snagles/gostructify/structify"
 	"github.com/urfave/cli"
-	"golang.org/x/tools/imports"
 )
 
 func main() {
@@ -37,66 +54,203 @@ func main() {
 	app.Commands = []cli.Command{
 		cli.Command{
 			Name: "mariadb",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				// username to use
 				cli.StringFlag{Name: "username", Usage: "username credentials to use `myuser`"},
 				cli.IntFlag{Name: "port", Usage: "database port to connect to `3306`", Value: 3306},
 				cli.StringFlag{Name: "hostname", Usage: "hostname to connect to `examplehost.com`"},
 				cli.StringFlag{Name: "database", Usage: "database name `application_db`"},
-				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names `users,admins`"},
-			},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,admins`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			}, connectionFlags...),
 			Usage: "generate structs from a mariadb database",
 			Action: func(c *cli.Context) error {
-				m := database.MariaDB{Hostname: c.String("hostname"), Username: c.String("username"), Password: getPassword(c, c.String("username")), Port: c.Int("port")}
-				process(m, c)
-				return nil
+				conn, err := connection(c)
+				if err != nil {
+					return err
+				}
+				m := database.MariaDB{Connection: conn}
+				return process(m, c)
 			},
 		},
 		cli.Command{
 			Name: "mysql",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				// username to use
 				cli.StringFlag{Name: "username", Usage: "username credentials to use `myuser`"},
 				cli.IntFlag{Name: "port", Usage: "database port to connect to `3306`", Value: 3306},
 				cli.StringFlag{Name: "hostname", Usage: "hostname to connect to `examplehost.com`"},
 				cli.StringFlag{Name: "database", Usage: "database name `application_db`"},
-				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names `users,admins`"},
-			},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,admins`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			}, connectionFlags...),
 			Usage: "generate structs from a mysql database",
 			Action: func(c *cli.Context) error {
-				m := database.MySQL{Hostname: c.String("hostname"), Username: c.String("username"), Password: getPassword(c, c.String("username")), Port: c.Int("port")}
-				process(m, c)
-				return nil
+				conn, err := connection(c)
+				if err != nil {
+					return err
+				}
+				m := database.MySQL{Connection: conn}
+				return process(m, c)
 			},
 		},
 		cli.Command{
 			Name: "postgresql",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				cli.StringFlag{Name: "username", Usage: "username credentials to use `myuser`"},
 				cli.IntFlag{Name: "port", Usage: "database port to connect to `5432`", Value: 5432},
 				cli.StringFlag{Name: "hostname", Usage: "hostname to connect to `examplehost.com`"},
 				cli.StringFlag{Name: "database", Usage: "database name `application_db`"},
-				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names `users,admins`"},
-			},
+				cli.StringFlag{Name: "schema", Usage: "list of comma separated schemas tables are listed from and resolved against in order, defaults to the search_path `public,sales`"},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,sales.orders`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			}, connectionFlags...),
 			Usage: "generate structs from a postgresql database",
 			Action: func(c *cli.Context) error {
-				p := database.PostgreSQL{Hostname: c.String("hostname"), Username: c.String("username"), Password: getPassword(c, c.String("username")), Port: c.Int("port")}
-				process(p, c)
-				return nil
+				conn, err := connection(c)
+				if err != nil {
+					return err
+				}
+				p := database.PostgreSQL{Connection: conn, Schemas: splitList(c.String("schema"))}
+				return process(p, c)
 			},
 		},
 		cli.Command{
 			Name: "vertica",
-			Flags: []cli.Flag{
-				cli.StringFlag{Name: "dsn", Usage: "dsn name `vertica`"},
+			Flags: append([]cli.Flag{
+				cli.StringFlag{Name: "dsn", Usage: "odbc data source name, the connection options are used without it `vertica`"},
+				cli.BoolFlag{Name: "odbc", Usage: "connect with the vertica odbc driver instead of the native driver"},
+				cli.StringFlag{Name: "username", Usage: "username credentials to use `myuser`"},
+				cli.IntFlag{Name: "port", Usage: "database port to connect to `5433`", Value: 5433},
+				cli.StringFlag{Name: "hostname", Usage: "hostname to connect to `examplehost.com`"},
+				cli.StringFlag{Name: "database", Usage: "schema name `application_db`"},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,admins`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			}, connectionFlags...),
+			Usage: "generate structs from a vertica database",
+			Action: func(c *cli.Context) error {
+				v := database.Vertica{DSN: c.String("dsn"), ODBC: c.Bool("odbc")}
+				if v.DSN == "" {
+					var err error
+					if v.Connection, err = connection(c); err != nil {
+						return err
+					}
+				}
+				return process(v, c)
+			},
+		},
+		cli.Command{
+			Name: "mssql",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{Name: "username", Usage: "username credentials to use `myuser`"},
+				cli.IntFlag{Name: "port", Usage: "database port to connect to `1433`", Value: 1433},
+				cli.StringFlag{Name: "hostname", Usage: "hostname to connect to `examplehost.com`"},
 				cli.StringFlag{Name: "database", Usage: "database name `application_db`"},
-				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names `users,admins`"},
+				cli.StringFlag{Name: "schema", Usage: "schema of table names that are not schema qualified `dbo`", Value: "dbo"},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,sales.orders`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			}, connectionFlags...),
+			Usage: "generate structs from a microsoft sql server database",
+			Action: func(c *cli.Context) error {
+				conn, err := connection(c)
+				if err != nil {
+					return err
+				}
+				m := database.MSSQL{Connection: conn, Schema: c.String("schema")}
+				return process(m, c)
 			},
-			Usage: "generate structs from a vertica database",
+		},
+		cli.Command{
+			Name: "sqlite",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "path", Usage: "path to the sqlite database file `application.db`"},
+				cli.StringFlag{Name: "database", Usage: "schema name, main or the name of an attached database `main`", Value: "main"},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,admins`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			},
+			Usage: "generate structs from a sqlite database file",
+			Action: func(c *cli.Context) error {
+				s := database.SQLite{Path: c.String("path")}
+				return process(s, c)
+			},
+		},
+		cli.Command{
+			Name: "ddl",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "dialect", Usage: "sql dialect of the statements `" + strings.Join(database.Dialects(), ",") + "`"},
+				cli.StringFlag{Name: "files", Usage: "list of comma separated sql files or directories of sql files `schema.sql`"},
+				cli.StringFlag{Name: "database", Usage: "database name, matched against schema qualified table names `application_db`"},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,admins`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			},
+			Usage: "generate structs from the create table statements of sql files",
+			Action: func(c *cli.Context) error {
+				s, err := database.NewSchema(c.String("dialect"))
+				if err != nil {
+					return err
+				}
+				files, err := sqlFiles(c.String("files"))
+				if err != nil {
+					return err
+				}
+				for _, f := range files {
+					if err := s.ApplyFile(f); err != nil {
+						return fmt.Errorf("Failed to parse %s", err)
+					}
+				}
+				return process(s, c)
+			},
+		},
+		cli.Command{
+			Name: "migrations",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "dialect", Usage: "sql dialect of the migrations `" + strings.Join(database.Dialects(), ",") + "`"},
+				cli.StringFlag{Name: "dir", Usage: "directory of numbered migration files `migrations`"},
+				cli.StringFlag{Name: "database", Usage: "database name, matched against schema qualified table names `application_db`"},
+				cli.StringFlag{Name: "tables", Usage: "list of comma separated table names or glob patterns, * for all tables `users,admins`"},
+				cli.StringSliceFlag{Name: "exclude-tables", Usage: "regular expression of table names to exclude, can be repeated `^tmp_`"},
+			},
+			Usage: "generate structs from the schema resulting from replaying a directory of up migrations",
+			Action: func(c *cli.Context) error {
+				s, err := database.LoadMigrations(c.String("dialect"), c.String("dir"))
+				if err != nil {
+					return fmt.Errorf("Failed to replay migrations: %s", err)
+				}
+				return process(s, c)
+			},
+		},
+		cli.Command{
+			Name: "generate",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "config, c", Usage: "project configuration file `gostructify.yaml`", Value: "gostructify.yaml"},
+				cli.StringSliceFlag{Name: "target", Usage: "name of a target to generate, can be repeated, every target by default `models`"},
+			},
+			Usage: "generate every target of a project configuration file",
+			Action: func(c *cli.Context) error {
+				return generateConfig(c, c.GlobalBool("check"))
+			},
+		},
+		cli.Command{
+			Name: "check",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "config, c", Usage: "project configuration file to check instead of the directives of the directories `gostructify.yaml`"},
+				cli.StringSliceFlag{Name: "target", Usage: "name of a target to check, can be repeated, every target by default `models`"},
+			},
+			Usage:     "check that the files of a project configuration file, or of the directives of the directories, are up to date",
+			ArgsUsage: "[directories, ./... to include subdirectories]",
 			Action: func(c *cli.Context) error {
-				v := database.Vertica{DSN: c.String("dsn")}
-				process(v, c)
-				return nil
+				if c.String("config") != "" {
+					return generateConfig(c, true)
+				}
+				return scan(c, "--check")
+			},
+		},
+		cli.Command{
+			Name:      "scan",
+			Usage:     "regenerate every table declared by go:generate gostructify or gostructify:table comments",
+			ArgsUsage: "[directories, ./... to include subdirectories]",
+			Action: func(c *cli.Context) error {
+				return scan(c)
 			},
 		},
 	}
@@ -117,11 +271,76 @@ func main() {
 		},
 		cli.StringFlag{
 			Name:  "tags",
-			Usage: "list of comma delimited tag options `json,gorm,sqlx,xml,csv`",
+			Usage: "list of comma delimited tag options `json,gorm,sqlx,xml,csv,validate`",
 		},
 		cli.StringFlag{
 			Name:  "methods",
-			Usage: "list of comma delmited method options `gorm`",
+			Usage: "list of comma delmited method options `gorm,sqlx`",
+		},
+		cli.StringFlag{
+			Name:  "template",
+			Usage: "list of comma separated text/template files redefining the default templates or defining new methods `repository.tmpl`",
+		},
+		cli.StringFlag{
+			Name:  "types",
+			Usage: "yaml file of go type overrides for database types, table.column names and column name patterns",
+		},
+		cli.BoolFlag{
+			Name:  "precise",
+			Usage: "map integers to the go type of their size and decimals to an exact decimal type",
+		},
+		cli.StringFlag{
+			Name:  "decimal",
+			Value: "shopspring",
+			Usage: "exact go type of decimal columns with --precise `shopspring,apd,string`",
+		},
+		cli.StringFlag{
+			Name:  "trim-prefix",
+			Usage: "prefix removed from table names before they are converted to struct names `tbl_`",
+		},
+		cli.StringFlag{
+			Name:  "trim-suffix",
+			Usage: "suffix removed from table names before they are converted to struct names `_tbl`",
+		},
+		cli.StringFlag{
+			Name:  "qualify-schemas",
+			Usage: "list of comma separated schemas whose struct names are prefixed with the schema, * for every schema `sales`",
+		},
+		cli.BoolFlag{
+			Name:  "singular",
+			Usage: "singularize table names, users generates the struct User",
+		},
+		cli.StringSliceFlag{
+			Name:  "rename-table",
+			Usage: "struct name of a table, can be repeated `tbl_usr=User`",
+		},
+		cli.StringSliceFlag{
+			Name:  "rename-column",
+			Usage: "field name of the column of every table or of a table.column or schema.table.column, can be repeated `users.usr_nm=Name`",
+		},
+		cli.StringFlag{
+			Name:  "initialisms",
+			Usage: "list of comma separated initialisms upper cased in addition to the golint initialisms `SKU,EAN`",
+		},
+		cli.BoolFlag{
+			Name:  "no-initialisms",
+			Usage: "keep the case of every word of names instead of upper casing initialisms, user_id generates UserId",
+		},
+		cli.BoolFlag{
+			Name:  "relations",
+			Usage: "add belongs to and has many fields for foreign keys between the generated structs",
+		},
+		cli.BoolFlag{
+			Name:  "views",
+			Usage: "include views and materialized views in table patterns such as *",
+		},
+		cli.StringFlag{
+			Name:  "save-snapshot",
+			Usage: "json or yaml file the introspected tables are saved to `schema.json`",
+		},
+		cli.StringFlag{
+			Name:  "from-snapshot",
+			Usage: "generate from the tables of a snapshot file instead of connecting to the database `schema.json`",
 		},
 		cli.BoolFlag{
 			Name:  "stdin",
@@ -132,10 +351,18 @@ func main() {
 			Usage:  "password for database connection",
 			EnvVar: "GOSTRUCTIFY_PASSWORD",
 		},
+		cli.StringFlag{
+			Name:  "password-command",
+			Usage: "shell command printing the password, used when no password is set `pass show db/app`",
+		},
 		cli.BoolFlag{
 			Name:  "dry-run",
 			Usage: "dry-run prints output to screen instead of a file",
 		},
+		cli.BoolFlag{
+			Name:  "check",
+			Usage: "compare the output with the existing file and print a diff instead of writing it, exits with status 1 when a file is out of date",
+		},
 	}
 
 	app.Name = "gostructify"
@@ -147,93 +374,472 @@ func main() {
 			Name: "snagles",
 		},
 	}
-	app.Run(os.Args)
+	if err := app.Run(os.Args); err != nil {
+		// files written before the failure are complete, they are reported so the run can be resumed
+		if len(written) > 0 {
+			logrus.Warnf("%d files were written before the failure: %s", len(written), strings.Join(written, ", "))
+		}
+		logrus.Errorf("%s", err)
+		os.Exit(exitCode(err))
+	}
+	if len(stale) > 0 {
+		logrus.Errorf("%d generated files are out of date: %s", len(stale), strings.Join(stale, ", "))
+		os.Exit(exitStale)
+	}
+}
+
+// exit codes of the failures scripted runs can react to
+const (
+	exitStale       = 1 // files found out of date by --check
+	exitFailure     = 2 // invalid options and any other failure
+	exitConnection  = 3 // the database could not be reached
+	exitUnknownType = 4 // a column type has no go type and needs a type override
+	exitWrite       = 5 // an output file could not be written
+)
+
+// writeError is a failure to write an output file
+type writeError struct {
+	file string
+	err  error
+}
+
+func (e *writeError) Error() string {
+	return fmt.Sprintf("writing %s: %s", e.file, e.err)
+}
+
+// exitCode returns the exit code of the failure
+func exitCode(err error) int {
+	var (
+		connection  *database.ConnectionError
+		unknownType *database.UnknownTypeError
+		write       *writeError
+	)
+	switch {
+	case errors.As(err, &connection):
+		return exitConnection
+	case errors.As(err, &unknownType):
+		return exitUnknownType
+	case errors.As(err, &write):
+		return exitWrite
+	}
+	return exitFailure
+}
+
+// target is an output file generated from the tables of one database
+type target struct {
+	gostructify.Options
+	dryRun bool
+	check  bool // compare the output with the existing file instead of writing it
 }
 
-func process(d database.Database, c *cli.Context) {
+// stale holds the output files found out of date by --check
+var stale []string
+
+// written holds the output files written so far
+var written []string
+
+// commandDatabases returns the comma separated databases of the command, or the database of the
+// connection url
+func commandDatabases(c *cli.Context) []string {
+	databases := c.String("database")
+	if databases == "" {
+		databases = database.Connection{URL: c.String("url")}.URLDatabase()
+	}
+	return strings.Split(databases, ",")
+}
+
+// process generates a file for each database of the command line options
+func process(d database.Database, c *cli.Context) error {
 	g := Generator{}
-	if c.GlobalString("directory") != "" && isDirectory(c.GlobalString("directory")) {
-		g.parsePackageDir(c.GlobalString("directory"))
-	} else {
-		g.parsePackageFiles(strings.Split(c.GlobalString("file"), ","))
-	}
-
-	for _, db := range strings.Split(c.String("database"), ",") {
-		// build the struct for each table
-		var buf bytes.Buffer
-		fmt.Fprintf(&buf, "// Code generated by gostructify command \"%s\"; DO NOT EDIT.\n", strings.Join(os.Args, " "))
-		fmt.Fprintf(&buf, "package %s", g.pkg.name)
-		fmt.Fprintf(&buf, "\n")
-		fmt.Fprintf(&buf, "\n")
-
-		for _, table := range strings.Split(c.String("tables"), ",") {
-			t, err := d.Build(db, table)
-			if err != nil {
-				logrus.Fatalf("Failed to generate file for %s.%s: %s", db, table, err)
-			}
-			buf.Write(structify.Generate(c, db, t))
+	dir := c.GlobalString("directory")
+	isDir := false
+	if dir != "" {
+		var err error
+		if isDir, err = isDirectory(dir); err != nil {
+			return err
 		}
+	}
+	if isDir {
+		if err := g.parsePackageDir(dir); err != nil {
+			return err
+		}
+	} else if err := g.parsePackageFiles(strings.Split(c.GlobalString("file"), ",")); err != nil {
+		return err
+	}
 
-		// Write the file unformatted
-		if c.GlobalBool("dry-run") {
-			f, err := ioutil.TempFile("", "tmp")
-			if err != nil {
-				logrus.Fatalf("failed to write temp file for dry run: %s", err)
+	var overrides database.TypeOverrides
+	if c.GlobalString("types") != "" {
+		var err error
+		if overrides, err = database.LoadTypeOverrides(c.GlobalString("types")); err != nil {
+			return fmt.Errorf("Failed to read type overrides: %s", err)
+		}
+	}
+	naming, err := namingOptions(c)
+	if err != nil {
+		return err
+	}
+	s, err := fromSnapshot(d, c)
+	if err != nil {
+		return err
+	}
+	d = s
+	var snapshot *database.Snapshot
+	if !c.GlobalBool("check") {
+		if snapshot, err = openSnapshot(c.GlobalString("save-snapshot")); err != nil {
+			return err
+		}
+	}
+
+	for _, db := range commandDatabases(c) {
+		output := c.GlobalString("file")
+		if output == "" {
+			// files starting with _ are ignored by the go tool, ddl and migrations have no database
+			baseName := "gostructify.go"
+			if db != "" {
+				baseName = fmt.Sprintf("%s_gostructify.go", db)
 			}
+			output = filepath.Join(g.pkg.dir, strings.ToLower(baseName))
+		}
+		err := generate(d, target{
+			Options: gostructify.Options{
+				Database:      db,
+				Tables:        splitList(c.String("tables")),
+				ExcludeTables: c.StringSlice("exclude-tables"),
+				Views:         c.GlobalBool("views"),
+				Precise:       c.GlobalBool("precise"),
+				Decimal:       c.GlobalString("decimal"),
+				Types:         overrides,
+				Struct: structify.Options{
+					Tags:         splitList(c.GlobalString("tags")),
+					Methods:      splitList(c.GlobalString("methods")),
+					NullableType: c.GlobalString("nullabletype"),
+					Relations:    c.GlobalBool("relations"),
+					Naming:       naming,
+					Templates:    splitList(c.GlobalString("template")),
+				},
+				Package:  g.pkg.name,
+				Output:   output,
+				Command:  headerCommand(c, db, output),
+				Snapshot: snapshot,
+			},
+			dryRun: c.GlobalBool("dry-run"),
+			check:  c.GlobalBool("check"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return saveSnapshot(snapshot, c.GlobalString("save-snapshot"))
+}
 
-			defer os.Remove(f.Name())
+// generate builds the selected tables of the target database and writes them to the output file
+func generate(d database.Database, t target) error {
+	t.Struct.Warn = logWarning
+	src, err := gostructify.Generate(context.Background(), d, t.Options)
+	if err != nil {
+		return fmt.Errorf("Failed to generate %s: %w", t.Output, err)
+	}
 
-			_, err = f.Write(buf.Bytes())
-			if err != nil {
-				logrus.Fatalf("writing tempfile output: %s", err)
-			}
+	if t.check {
+		return checkOutput(t.Output, src)
+	}
+	if t.dryRun {
+		fmt.Println(string(src))
+		return nil
+	}
+	if err := writeFile(t.Output, src); err != nil {
+		return err
+	}
+	written = append(written, t.Output)
+	fmt.Printf("Wrote file: %s\n", t.Output)
+	return nil
+}
 
-			// Run go imports to gofmt and goimports the file
-			src, err := imports.Process(f.Name(), buf.Bytes(), nil)
-			if err != nil {
-				logrus.Fatalf("processing imports: %s", err)
-			}
-			fmt.Println(string(src))
-		} else {
-			output := c.GlobalString("file")
-			if output == "" {
-				baseName := fmt.Sprintf("%s_gostructify.go", db)
-				output = filepath.Join(g.pkg.dir, strings.ToLower(baseName))
-			}
-			err := ioutil.WriteFile(output, buf.Bytes(), 0644)
-			if err != nil {
-				logrus.Fatalf("writing output: %s", err)
-			}
+// logWarning logs the warnings of the database and structify packages
+func logWarning(message string) {
+	logrus.Warn(message)
+}
 
-			// Run go imports to gofmt and goimports the file
-			src, err := imports.Process(output, buf.Bytes(), nil)
-			if err != nil {
-				logrus.Fatalf("processing imports: %s", err)
-			}
+// writeFile replaces the file through a temporary file of the same directory, so that a failed write
+// never leaves a partially written file
+func writeFile(name string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".tmp")
+	if err != nil {
+		return &writeError{file: name, err: err}
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return &writeError{file: name, err: err}
+	}
+	if err := f.Close(); err != nil {
+		return &writeError{file: name, err: err}
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return &writeError{file: name, err: err}
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		return &writeError{file: name, err: err}
+	}
+	return nil
+}
 
-			err = ioutil.WriteFile(output, src, 0644)
-			if err != nil {
-				logrus.Fatalf("writing output: %s", err)
-			}
-			fmt.Printf("Wrote file: %s", output)
+// fromSnapshot returns the snapshot of the --from-snapshot option in place of the database when set
+func fromSnapshot(d database.Database, c *cli.Context) (database.Database, error) {
+	if c.GlobalString("from-snapshot") == "" {
+		return d, nil
+	}
+	s, err := database.LoadSnapshot(c.GlobalString("from-snapshot"))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read snapshot: %s", err)
+	}
+	return s, nil
+}
+
+// openSnapshot returns the snapshot the built tables are saved to, tables of the existing file are
+// kept unless they are built again. It returns nil when no snapshot is saved.
+func openSnapshot(file string) (*database.Snapshot, error) {
+	if file == "" {
+		return nil, nil
+	}
+	s, err := database.OpenSnapshot(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read snapshot: %s", err)
+	}
+	return s, nil
+}
+
+func saveSnapshot(s *database.Snapshot, file string) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.Save(file); err != nil {
+		return &writeError{file: file, err: err}
+	}
+	written = append(written, file)
+	fmt.Printf("Wrote snapshot: %s\n", file)
+	return nil
+}
+
+// checkOutput compares the output with the existing file and prints the difference when
+// the file is missing or out of date
+func checkOutput(output string, src []byte) error {
+	existing, err := ioutil.ReadFile(output)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading output: %s", err)
+	}
+	// the whole file is compared, the header only records the options and fingerprint of the source
+	// so it is the same on every machine and a change of it is a change of the file
+	if diff := unifiedDiff(output, output+" (generated)", existing, src); diff != "" {
+		stale = append(stale, output)
+		fmt.Printf("Stale file: %s\n%s", output, diff)
+		return nil
+	}
+	fmt.Printf("Up to date: %s\n", output)
+	return nil
+}
+
+// splitList splits a comma separated option, an empty option is an empty list
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// connectionFlags are the connection options of the database server commands
+var connectionFlags = []cli.Flag{
+	cli.StringFlag{Name: "url", Usage: "database url, the other connection options override its parts `postgres://app@db.example.com/app?sslmode=verify-full`"},
+	cli.StringFlag{Name: "socket", Usage: "unix socket path, or the socket directory for postgresql `/var/run/mysqld/mysqld.sock`"},
+	cli.StringFlag{Name: "sslmode", Usage: "encryption of the connection, require does not verify the server certificate `disable,require,verify-ca,verify-full`"},
+	cli.StringFlag{Name: "sslrootcert", Usage: "CA certificate file verifying the server `ca.pem`"},
+	cli.StringFlag{Name: "sslcert", Usage: "client certificate file `client.pem`"},
+	cli.StringFlag{Name: "sslkey", Usage: "client certificate key file `client.key`"},
+	cli.DurationFlag{Name: "connect-timeout", Usage: "timeout of connecting to the database `10s`"},
+	cli.StringSliceFlag{Name: "param", Usage: "extra driver parameter, can be repeated `application_name=gostructify`"},
+}
+
+// connection returns the connection options of a database server command, the password is read
+// unless the url holds it
+func connection(c *cli.Context) (database.Connection, error) {
+	params, err := keyValues(c.StringSlice("param"))
+	if err != nil {
+		return database.Connection{}, err
+	}
+	conn := database.Connection{
+		URL:            c.String("url"),
+		Hostname:       c.String("hostname"),
+		Socket:         c.String("socket"),
+		Username:       c.String("username"),
+		SSLMode:        c.String("sslmode"),
+		SSLRootCert:    c.String("sslrootcert"),
+		SSLCert:        c.String("sslcert"),
+		SSLKey:         c.String("sslkey"),
+		ConnectTimeout: c.Duration("connect-timeout"),
+		Params:         params,
+		Warn:           logWarning,
+	}
+	// the default port of the flag would override the port of the url
+	if c.IsSet("port") || conn.URL == "" {
+		conn.Port = c.Int("port")
+	}
+	if err := conn.Validate(); err != nil {
+		return conn, err
+	}
+	if !conn.HasPassword() {
+		if conn.Password, err = getPassword(c, c.Command.Name, conn, commandDatabases(c)); err != nil {
+			return conn, err
+		}
+	}
+	return conn, nil
+}
+
+// namingOptions returns the naming options of the command line
+func namingOptions(c *cli.Context) (structify.Naming, error) {
+	tables, err := renames(c.GlobalStringSlice("rename-table"))
+	if err != nil {
+		return structify.Naming{}, err
+	}
+	columns, err := renames(c.GlobalStringSlice("rename-column"))
+	if err != nil {
+		return structify.Naming{}, err
+	}
+	return structify.Naming{
+		TrimPrefix:     c.GlobalString("trim-prefix"),
+		TrimSuffix:     c.GlobalString("trim-suffix"),
+		Singular:       c.GlobalBool("singular"),
+		QualifySchemas: splitList(c.GlobalString("qualify-schemas")),
+		Tables:         tables,
+		Columns:        columns,
+		Initialisms:    splitList(c.GlobalString("initialisms")),
+		NoInitialisms:  c.GlobalBool("no-initialisms"),
+	}, nil
+}
+
+// renames parses name=GoName renames
+func renames(values []string) (map[string]string, error) {
+	m, err := keyValues(values)
+	if err != nil {
+		return nil, err
+	}
+	for name, goName := range m {
+		if !token.IsIdentifier(goName) {
+			return nil, fmt.Errorf("Invalid rename %s=%s, %s is not a go identifier", name, goName, goName)
+		}
+	}
+	return m, nil
+}
+
+// keyValues parses key=value options
+func keyValues(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	m := map[string]string{}
+	for _, v := range values {
+		i := strings.Index(v, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid option %q, expected key=value", v)
 		}
+		m[v[:i]] = v[i+1:]
 	}
+	return m, nil
 }
 
-func getPassword(c *cli.Context, username string) string {
-	if c.GlobalString("password") == "" {
-		if c.GlobalBool("stdin") {
-			fmt.Printf("Enter password for user %s (if no password, leave blank): ", username)
-			password, err := gopass.GetPasswd()
-			if err != nil {
-				logrus.Fatalf("Failed to retrieve password: %s", err)
+// sqlFiles expands the comma separated list of files and directories to the sql files to read,
+// files of a directory are read in lexical order
+func sqlFiles(list string) ([]string, error) {
+	var files []string
+	for _, name := range strings.Split(list, ",") {
+		if name == "" {
+			continue
+		}
+		dir, err := isDirectory(name)
+		if err != nil {
+			return nil, err
+		}
+		if !dir {
+			files = append(files, name)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(name, "*.sql"))
+		if err != nil {
+			return nil, fmt.Errorf("listing sql files of %s: %s", name, err)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// getPassword returns the password of the connection to the databases from, in order, --password,
+// --password-command and the credential files of the driver, or prompts for it with --stdin. An empty
+// password is returned when a credential file holds it, the driver reads it when connecting to each
+// database.
+func getPassword(c *cli.Context, driver string, conn database.Connection, databases []string) (string, error) {
+	// no connection is made when generating from a snapshot
+	if c.GlobalString("from-snapshot") != "" {
+		return "", nil
+	}
+	if password := c.GlobalString("password"); password != "" {
+		return password, nil
+	}
+	if command := c.GlobalString("password-command"); command != "" {
+		return passwordCommand(command)
+	}
+	found, err := credentialFile(driver, conn, databases)
+	if err != nil || found {
+		return "", err
+	}
+	if c.GlobalBool("stdin") {
+		fmt.Printf("Enter password for user %s (if no password, leave blank): ", conn.Username)
+		password, err := gopass.GetPasswd()
+		if err != nil {
+			return "", fmt.Errorf("Failed to retrieve password: %s", err)
+		}
+		return string(password), nil
+	}
+	return "", fmt.Errorf("Missing password. Password either needs to be set as an env variable `GOSTRUCTIFY_PASSWORD=password`, passed on the command line, read by --password-command, stored in ~/.pgpass or ~/.my.cnf, or using the --stdin option to be prompted")
+}
+
+// credentialFile reports whether the credential file of the driver holds a password for the
+// connection, ~/.pgpass for postgresql and the [client] group of the option files for mysql and mariadb.
+// The password file of postgresql needs an entry for each of the databases, an empty database is the
+// database of the url.
+func credentialFile(driver string, conn database.Connection, databases []string) (bool, error) {
+	var (
+		found bool
+		err   error
+	)
+	switch driver {
+	case "postgresql":
+		for _, db := range databases {
+			if _, found, err = conn.PgpassPassword(db); err != nil || !found {
+				break
 			}
-			return string(password)
 		}
-		logrus.Fatalf("Missing password. Password either needs to be set as an env variable `GOSTRUCTIFY_PASSWORD=password`, passed on the command line, or using the --stdin option to be prompted")
+	case "mysql":
+		_, _, found, err = conn.OptionFileUser("client")
+	case "mariadb":
+		_, _, found, err = conn.OptionFileUser("client", "client-mariadb")
 	}
+	return found, err
+}
 
-	return c.GlobalString("password")
+// passwordCommand runs the command with the shell and returns its output without the trailing
+// newline. The command can prompt on the terminal, its stdin and stderr are kept.
+func passwordCommand(command string) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.Command(shell, flag, command)
+	cmd.Stdin, cmd.Stderr = os.Stdin, os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to run the password command: %s", err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
 }
 
 const tpl = `NAME:
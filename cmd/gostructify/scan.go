@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// scan regenerates every table declared by gostructify directives in the package
+// directories passed as arguments. Each directive is run as if it was invoked by
+// go generate from the directory of the file declaring it, with the extra global flags.
+func scan(c *cli.Context, extra ...string) error {
+	dirs := []string(c.Args())
+	if len(dirs) == 0 {
+		dir := c.GlobalString("directory")
+		if dir == "" {
+			dir = "."
+		}
+		dirs = []string{dir}
+	}
+
+	var packages []string
+	for _, dir := range dirs {
+		ds, err := packageDirs(dir)
+		if err != nil {
+			return err
+		}
+		packages = append(packages, ds...)
+	}
+
+	global := globalArgs(c)
+	for _, dir := range packages {
+		g := Generator{}
+		if err := g.parsePackageDir(dir); err != nil {
+			return err
+		}
+		ds, err := g.directives()
+		if err != nil {
+			return fmt.Errorf("scanning %s: %s", dir, err)
+		}
+		for _, d := range mergeDirectives(ds) {
+			args := []string{c.App.Name}
+			args = append(args, global...)
+			args = append(args, extra...)
+			args = append(args, "--directory", dir)
+			args = append(args, directiveArgs(d, dir)...)
+			fmt.Printf("Running directive %s\n", d)
+			if err := c.App.Run(args); err != nil {
+				return fmt.Errorf("running directive %s: %w", d, err)
+			}
+		}
+	}
+	return nil
+}
+
+// packageDirs returns the directory, or when it ends with /... every directory
+// below it containing buildable go files.
+func packageDirs(dir string) ([]string, error) {
+	if !strings.HasSuffix(dir, "...") {
+		return []string{dir}, nil
+	}
+	root := filepath.Clean(strings.TrimSuffix(dir, "..."))
+	var dirs []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+		if _, err := build.Default.ImportDir(path, 0); err == nil {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %s", root, err)
+	}
+	return dirs, nil
+}
+
+// globalArgs rebuilds the global flags set on the scan invocation so that they are
+// passed on to every directive, with the exception of the package location flags.
+func globalArgs(c *cli.Context) []string {
+	var args []string
+	for _, f := range setFlags(c, c.App.Flags, true) {
+		if f.name != "directory" && f.name != "file" {
+			args = append(args, f.args()...)
+		}
+	}
+	return args
+}
+
+// directivePathFlags are the flags of a directive holding paths: the output --file, the --types and
+// --template files, the sqlite --path, the ddl --files and the migrations --dir
+var directivePathFlags = []string{"file", "types", "template", "path", "files", "dir"}
+
+// directiveArgs returns the directive arguments with the relative paths of its path flags resolved
+// against the directory of the file declaring the directive, as go generate runs the directive from
+// that directory.
+func directiveArgs(d Directive, dir string) []string {
+	return resolvePaths(d.Args, dir, directivePathFlags...)
+}
+
+// resolvePaths returns the arguments with the relative paths of the flags resolved against the
+// directory, the paths of comma separated lists are resolved one by one
+func resolvePaths(args []string, dir string, names ...string) []string {
+	flags := map[string]bool{}
+	for _, name := range names {
+		flags[name] = true
+	}
+	resolved := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		name := strings.TrimLeft(a, "-")
+		if !strings.HasPrefix(a, "-") {
+			resolved = append(resolved, a)
+			continue
+		}
+		if j := strings.Index(name, "="); j >= 0 {
+			if flags[name[:j]] {
+				a = a[:len(a)-len(name)+j+1] + resolveList(name[j+1:], dir)
+			}
+			resolved = append(resolved, a)
+			continue
+		}
+		resolved = append(resolved, a)
+		if flags[name] && i+1 < len(args) {
+			i++
+			resolved = append(resolved, resolveList(args[i], dir))
+		}
+	}
+	return resolved
+}
+
+// resolveList resolves the relative paths of a comma separated list against the directory
+func resolveList(list, dir string) string {
+	paths := splitList(list)
+	for i, path := range paths {
+		if path != "" && !filepath.IsAbs(path) {
+			paths[i] = filepath.Join(dir, path)
+		}
+	}
+	return strings.Join(paths, ",")
+}
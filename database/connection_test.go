@@ -0,0 +1,29 @@
+package database
+
+import "testing"
+
+func TestVerticaODBC(t *testing.T) {
+	tests := []struct {
+		connection Connection
+		want       string
+	}{
+		{
+			connection: Connection{URL: "vertica://dbadmin@127.0.0.1/vdb", Password: "secret"},
+			want:       "Driver={Vertica};Servername={127.0.0.1};Port={5433};Database={vdb};UID={dbadmin};PWD={secret};",
+		},
+		{
+			connection: Connection{Hostname: "db.example.com", Port: 5434, Username: "app", Password: "p;w}d={x", SSLMode: "require", Params: map[string]string{"Label": "a b"}},
+			want:       "Driver={Vertica};Servername={db.example.com};Port={5434};Database={};UID={app};PWD={p;w}}d={x};SSLMode={require};Label={a b};",
+		},
+	}
+	for _, test := range tests {
+		got, err := test.connection.verticaODBC()
+		if err != nil {
+			t.Errorf("%+v: %s", test.connection, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%+v:\ngot  %s\nwant %s", test.connection, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package database
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// PgpassPassword returns the password of the first entry of the postgresql password file matching the
+// connection to the database, the file is PGPASSFILE or ~/.pgpass. Entries are
+// hostname:port:database:username:password lines where * matches any value, an empty database
+// matches the entries of every database. Like libpq the file is not read, with a warning, when the
+// group or others can access it, and the username defaults to the user running gostructify.
+func (c Connection) PgpassPassword(database string) (string, bool, error) {
+	c, err := c.resolve()
+	if err != nil {
+		return "", false, err
+	}
+	path := os.Getenv("PGPASSFILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false, nil
+		}
+		path = filepath.Join(home, ".pgpass")
+	}
+	f, err := c.openCredentials(path, 0077)
+	if f == nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	// connections over a unix socket match the localhost entries
+	host := c.Hostname
+	if c.Socket != "" || host == "" {
+		host = "localhost"
+	}
+	username := c.Username
+	if username == "" {
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+	}
+	want := []string{host, strconv.Itoa(c.port(5432)), c.databaseName(database), username}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := pgpassFields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		match := true
+		for i, value := range want {
+			if fields[i] != "*" && fields[i] != value && !(i == 2 && value == "") {
+				match = false
+				break
+			}
+		}
+		if match {
+			return fields[4], true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", false, fmt.Errorf("reading %s: %s", path, err)
+	}
+	return "", false, nil
+}
+
+// pgpassFields splits a line of the password file on the colons, a backslash escapes a colon or a
+// backslash
+func pgpassFields(line string) []string {
+	var (
+		fields []string
+		field  strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			i++
+			field.WriteByte(line[i])
+		case line[i] == ':' && len(fields) < 4:
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteByte(line[i])
+		}
+	}
+	return append(fields, field.String())
+}
+
+// OptionFileUser returns the user and password of the groups of the mysql option files, the
+// [client] group for mysql and mariadb. The files are /etc/my.cnf, /etc/mysql/my.cnf,
+// $MYSQL_HOME/my.cnf and ~/.my.cnf, options of later files and groups override earlier ones. Like the
+// mysql client the files others can write to are not read, with a warning, and !include directives are
+// not followed.
+func (c Connection) OptionFileUser(groups ...string) (username, password string, found bool, err error) {
+	paths := []string{"/etc/my.cnf", "/etc/mysql/my.cnf"}
+	if home := os.Getenv("MYSQL_HOME"); home != "" {
+		paths = append(paths, filepath.Join(home, "my.cnf"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".my.cnf"))
+	}
+	for _, path := range paths {
+		options, err := c.readOptionFile(path, groups)
+		if err != nil {
+			return "", "", false, err
+		}
+		if value, ok := options["user"]; ok {
+			username = value
+		}
+		if value, ok := options["password"]; ok {
+			password, found = value, true
+		}
+	}
+	return username, password, found, nil
+}
+
+// readOptionFile returns the options of the groups of a mysql option file, nil when the file does
+// not exist. Option names use underscores or dashes, values may be quoted.
+func (c Connection) readOptionFile(path string, groups []string) (map[string]string, error) {
+	f, err := c.openCredentials(path, 0002)
+	if f == nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	read := map[string]bool{}
+	for _, g := range groups {
+		read[g] = true
+	}
+	options := map[string]string{}
+	var group string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';' || line[0] == '!':
+			continue
+		case line[0] == '[':
+			group = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		case !read[group]:
+			continue
+		}
+		name, value := line, ""
+		if i := strings.Index(line, "="); i >= 0 {
+			name, value = strings.TrimSpace(line[:i]), optionValue(strings.TrimSpace(line[i+1:]))
+		}
+		options[strings.Replace(name, "-", "_", -1)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %s", path, err)
+	}
+	return options, nil
+}
+
+// optionValue unquotes the value of an option, the comment ending an unquoted value is removed
+func optionValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			value = value[1 : end+1]
+		}
+	} else if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\s`, " ", `\\`, `\`).Replace(value)
+}
+
+// openCredentials opens a credential file, nil without an error when it does not exist. Files with
+// any of the permission bits of the mask are skipped with a warning as if they did not exist, except
+// on windows.
+func (c Connection) openCredentials(path string, mask os.FileMode) (*os.File, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %s", path, err)
+	}
+	if info.IsDir() {
+		return nil, nil
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&mask != 0 {
+		c.warnf("%s is not read as its permissions %s are too open", path, info.Mode().Perm())
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %s", path, err)
+	}
+	return f, nil
+}
+
+// withPgpass returns the connection with the password of the password file when no password is set
+func (c Connection) withPgpass(database string) (Connection, error) {
+	if c.HasPassword() {
+		return c, nil
+	}
+	password, ok, err := c.PgpassPassword(database)
+	if ok {
+		c.Password = password
+	}
+	return c, err
+}
+
+// withOptionFile returns the connection with the password of the option files when no password is
+// set, and their user when no username is set
+func (c Connection) withOptionFile(groups ...string) (Connection, error) {
+	if c.HasPassword() {
+		return c, nil
+	}
+	username, password, ok, err := c.OptionFileUser(groups...)
+	if err != nil || !ok {
+		return c, err
+	}
+	if resolved, _ := c.resolve(); resolved.Username == "" {
+		c.Username = username
+	}
+	c.Password = password
+	return c, nil
+}
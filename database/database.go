@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Database builds the table structure of a database to generate structs from
+type Database interface {
+	// Build retrieves the table structure of the table in the database
+	Build(ctx context.Context, database, table string) (*Table, error)
+	// ListTables returns the names of the tables in the database
+	ListTables(ctx context.Context, database string) ([]string, error)
+	// ListViews returns the names of the views and materialized views in the database
+	ListViews(ctx context.Context, database string) ([]string, error)
+}
+
+// ConnectionError is returned when the database can not be reached
+type ConnectionError struct {
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("connecting to the database: %s", e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// open opens the database and checks that it can be reached, failures are returned as a ConnectionError
+func open(ctx context.Context, driverName, dataSourceName string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, &ConnectionError{Err: err}
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, &ConnectionError{Err: err}
+	}
+	return db, nil
+}
+
+// queryType returns the type of the table read by the catalog query, tables missing from
+// the catalog are reported as base tables
+func queryType(ctx context.Context, db *sql.DB, query string, args ...interface{}) (TableType, error) {
+	var catalogType string
+	err := db.QueryRowContext(ctx, query, args...).Scan(&catalogType)
+	if err == sql.ErrNoRows {
+		return BaseTable, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return tableType(catalogType), nil
+}
+
+// scanColumn reads a column from a catalog query returning the column name, type, nullability,
+// length, precision, scale and default followed by the extra columns of the query
+func scanColumn(rows *sql.Rows, extra ...interface{}) (Column, error) {
+	var (
+		c                        Column
+		length, precision, scale sql.NullInt64
+		defaultValue             sql.NullString
+	)
+	dest := append([]interface{}{&c.Name, &c.DatabaseType, &c.DatabaseNullable, &length, &precision, &scale, &defaultValue}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return c, err
+	}
+	c.Length, c.Precision, c.Scale = int(length.Int64), int(precision.Int64), int(scale.Int64)
+	if defaultValue.Valid {
+		c.Default = &defaultValue.String
+	}
+	return c, nil
+}
+
+// queryIndexes reads the indexes of a table from a catalog query returning the index name, whether
+// it is the primary key, whether it is unique and the column name ordered by index and key position
+func queryIndexes(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]Index, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var indexes []Index
+	for rows.Next() {
+		var (
+			name, column    string
+			primary, unique int
+		)
+		if err := rows.Scan(&name, &primary, &unique, &column); err != nil {
+			return nil, err
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, Index{Name: name, Primary: primary == 1, Unique: unique == 1})
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column)
+	}
+	return indexes, rows.Err()
+}
+
+// queryForeignKeys reads the foreign keys of the table of the schema from a catalog query returning the
+// constraint name, the column name, the referenced table name, the referenced column name and the
+// referenced schema ordered by constraint and key position
+func queryForeignKeys(ctx context.Context, db *sql.DB, query, schema, table string) ([]ForeignKey, error) {
+	rows, err := db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []ForeignKey
+	for rows.Next() {
+		var name, column, referencedTable, referencedColumn, referencedSchema string
+		if err := rows.Scan(&name, &column, &referencedTable, &referencedColumn, &referencedSchema); err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 || keys[len(keys)-1].Name != name {
+			keys = append(keys, ForeignKey{Name: name, ReferencedTable: referencedTable})
+			if referencedSchema != schema {
+				keys[len(keys)-1].ReferencedSchema = referencedSchema
+			}
+		}
+		keys[len(keys)-1].Columns = append(keys[len(keys)-1].Columns, column)
+		keys[len(keys)-1].ReferencedColumns = append(keys[len(keys)-1].ReferencedColumns, referencedColumn)
+	}
+	return keys, rows.Err()
+}
+
+// scanNames reads a single column of names from the rows
+func scanNames(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
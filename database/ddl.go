@@ -0,0 +1,659 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	wordToken   tokenKind = iota // keywords and unquoted identifiers
+	identToken                   // quoted identifiers
+	stringToken                  // string literals
+	numberToken
+	symbolToken
+)
+
+// token is a single lexical element of a DDL statement
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// is reports whether the token is the unquoted keyword, ignoring case
+func (t token) is(keyword string) bool {
+	return t.kind == wordToken && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == symbolToken && t.text == symbol
+}
+
+// tokenize splits the sql text into tokens, skipping whitespace and comments
+func tokenize(dialect, sql string) ([]token, error) {
+	var tokens []token
+	line := 1
+	hashComments := dialect == "mariadb" || dialect == "mysql"
+	bracketIdents := dialect == "mssql" || dialect == "sqlite"
+	for i := 0; i < len(sql); {
+		ch := sql[i]
+		switch {
+		case ch == '\n':
+			line++
+			i++
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\f':
+			i++
+		case ch == '-' && strings.HasPrefix(sql[i:], "--"), ch == '#' && hashComments:
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			i += end
+		case ch == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(sql[i:i+2+end], "\n")
+			i += end + 4
+		case ch == '\'' || ch == '"' || ch == '`' || (ch == '[' && bracketIdents):
+			closing := ch
+			kind := identToken
+			if ch == '\'' {
+				kind = stringToken
+			}
+			if ch == '[' {
+				closing = ']'
+			}
+			start := line
+			var text []byte
+			j := i + 1
+			for ; j < len(sql); j++ {
+				if sql[j] == '\\' && kind == stringToken && hashComments && j+1 < len(sql) {
+					j++
+					text = append(text, sql[j])
+					continue
+				}
+				if sql[j] == closing {
+					// doubled quotes are escaped quotes
+					if j+1 < len(sql) && sql[j+1] == closing && closing != ']' {
+						text = append(text, closing)
+						j++
+						continue
+					}
+					break
+				}
+				if sql[j] == '\n' {
+					line++
+				}
+				text = append(text, sql[j])
+			}
+			if j >= len(sql) {
+				return nil, fmt.Errorf("line %d: unterminated quoted text", start)
+			}
+			tokens = append(tokens, token{kind: kind, text: string(text), line: start})
+			i = j + 1
+		case ch == '$' && dollarTag(sql[i:]) != "":
+			// postgres dollar quoted strings, ex: $body$ ... $body$
+			tag := dollarTag(sql[i:])
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated dollar quoted string", line)
+			}
+			text := sql[i+len(tag) : i+len(tag)+end]
+			tokens = append(tokens, token{kind: stringToken, text: text, line: line})
+			line += strings.Count(text, "\n")
+			i += 2*len(tag) + end
+		case isDigit(ch) || (ch == '.' && i+1 < len(sql) && isDigit(sql[i+1])):
+			j := i
+			for j < len(sql) && (isDigit(sql[j]) || sql[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: sql[i:j], line: line})
+			i = j
+		case isWordStart(ch):
+			j := i
+			for j < len(sql) && (isWordStart(sql[j]) || isDigit(sql[j]) || sql[j] == '$') {
+				j++
+			}
+			tokens = append(tokens, token{kind: wordToken, text: sql[i:j], line: line})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: symbolToken, text: string(ch), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// dollarTag returns the opening tag of a dollar quoted string or an empty string
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		if s[j] == '$' {
+			return s[:j+1]
+		}
+		if !isWordStart(s[j]) && !(j > 1 && isDigit(s[j])) {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isWordStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch >= 0x80
+}
+
+// statements splits the tokens on semicolons and the GO batch separators of sql server scripts,
+// a GO on a line of its own
+func statements(tokens []token) [][]token {
+	var stmts [][]token
+	var current []token
+	for i, t := range tokens {
+		if t.isSymbol(";") || batchSeparator(tokens, i) {
+			if len(current) > 0 {
+				stmts = append(stmts, current)
+			}
+			current = nil
+			continue
+		}
+		if len(current) == 0 && t.is("go") {
+			continue
+		}
+		current = append(current, t)
+	}
+	if len(current) > 0 {
+		stmts = append(stmts, current)
+	}
+	return stmts
+}
+
+// batchSeparator reports whether the token at i is a GO alone on its line
+func batchSeparator(tokens []token, i int) bool {
+	return tokens[i].is("go") && (i == 0 || tokens[i-1].line < tokens[i].line) &&
+		(i == len(tokens)-1 || tokens[i+1].line > tokens[i].line)
+}
+
+// parser walks the tokens of a single statement
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: symbolToken}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// line returns the line of the current token for error reporting
+func (p *parser) line() int {
+	if len(p.tokens) == 0 {
+		return 0
+	}
+	if p.done() {
+		return p.tokens[len(p.tokens)-1].line
+	}
+	return p.tokens[p.pos].line
+}
+
+// keyword consumes the keywords if they are the next tokens
+func (p *parser) keyword(words ...string) bool {
+	for i, w := range words {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+// identifier consumes a single, possibly quoted, identifier
+func (p *parser) identifier(fold bool) (string, error) {
+	t := p.next()
+	switch t.kind {
+	case identToken:
+		return t.text, nil
+	case wordToken:
+		if fold {
+			return strings.ToLower(t.text), nil
+		}
+		return t.text, nil
+	}
+	return "", fmt.Errorf("expected identifier, found %q", t.text)
+}
+
+// qualifiedName consumes a possibly schema qualified name and returns the qualifier and the name
+func (p *parser) qualifiedName(fold bool) (string, string, error) {
+	var parts []string
+	for {
+		part, err := p.identifier(fold)
+		if err != nil {
+			return "", "", err
+		}
+		parts = append(parts, part)
+		if !p.peek().isSymbol(".") {
+			break
+		}
+		p.next()
+	}
+	// catalog.schema.table names keep the schema as qualifier
+	if len(parts) == 1 {
+		return "", parts[0], nil
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], nil
+}
+
+// group consumes a parenthesized group and returns the tokens between the parentheses
+func (p *parser) group() ([]token, error) {
+	if !p.peek().isSymbol("(") {
+		return nil, fmt.Errorf("expected (, found %q", p.peek().text)
+	}
+	start := p.pos
+	depth := 0
+	for !p.done() {
+		t := p.next()
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+			if depth == 0 {
+				return p.tokens[start+1 : p.pos-1], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unbalanced parentheses")
+}
+
+// splitList splits the tokens on commas that are not nested in parentheses
+func splitList(tokens []token) [][]token {
+	var items [][]token
+	depth := 0
+	start := 0
+	for i, t := range tokens {
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+		case t.isSymbol(",") && depth == 0:
+			items = append(items, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		items = append(items, tokens[start:])
+	}
+	return items
+}
+
+// tableConstraintKeywords start a table level element of a create table statement
+var tableConstraintKeywords = []string{"constraint", "primary", "unique", "foreign", "check", "key", "index", "fulltext", "spatial", "exclude", "period"}
+
+// columnStopKeywords end the type of a column definition
+var columnStopKeywords = map[string]bool{
+	"not": true, "null": true, "default": true, "primary": true, "unique": true, "references": true,
+	"check": true, "constraint": true, "collate": true, "auto_increment": true, "autoincrement": true,
+	"identity": true, "generated": true, "comment": true, "on": true, "charset": true, "as": true,
+	"key": true, "encoding": true, "storage": true, "compression": true, "invisible": true, "visible": true,
+	"column_format": true, "sparse": true, "rowguidcol": true, "filestream": true, "persisted": true,
+	"using": true, "first": true, "after": true,
+}
+
+// columnModifiers are attributes written as part of a numeric type
+var columnModifiers = map[string]bool{"unsigned": true, "signed": true, "zerofill": true}
+
+// notNullTypes are the serial types, they imply a not null column filled in by the database
+var notNullTypes = map[string]map[string]bool{
+	"mariadb":    map[string]bool{"serial": true},
+	"mysql":      map[string]bool{"serial": true},
+	"postgresql": map[string]bool{"smallserial": true, "serial2": true, "serial": true, "serial4": true, "bigserial": true, "serial8": true},
+}
+
+// columnDefinition is a column parsed from a DDL statement
+type columnDefinition struct {
+	Column
+	primaryKey bool
+	unique     bool
+	references *ForeignKey
+}
+
+// setSize sets the length or the precision and scale of the column from the size written after its type,
+// ex: (100), (10,2) or (max)
+func (c *columnDefinition) setSize(size []token) {
+	var numbers []int
+	for _, t := range size {
+		switch {
+		case t.kind == numberToken:
+			n, _ := strconv.Atoi(t.text)
+			numbers = append(numbers, n)
+		case t.is("max"):
+			numbers = append(numbers, -1)
+		}
+	}
+	switch {
+	case len(numbers) == 0:
+	case decimalTypes[c.DatabaseType]:
+		c.Precision = numbers[0]
+		if len(numbers) > 1 {
+			c.Scale = numbers[1]
+		}
+	case sizedTypes[c.DatabaseType]:
+		c.Length = numbers[0]
+	}
+}
+
+// defaultExpression consumes the expression of a default clause up to the next column constraint
+// and returns its text, ex: DEFAULT nextval('users_id_seq'::regclass) NOT NULL
+func defaultExpression(p *parser) string {
+	var tokens []token
+	depth := 0
+	for !p.done() {
+		t := p.peek()
+		if depth == 0 && len(tokens) > 0 && t.kind == wordToken && columnStopKeywords[strings.ToLower(t.text)] {
+			break
+		}
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+		}
+		tokens = append(tokens, p.next())
+	}
+	return sqlText(tokens)
+}
+
+// sqlText writes the tokens back as sql text
+func sqlText(tokens []token) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 {
+			previous := tokens[i-1]
+			attached := t.isSymbol(")") || t.isSymbol(",") || t.isSymbol(".") || t.isSymbol(":") ||
+				(t.isSymbol("(") && previous.kind == wordToken) ||
+				previous.isSymbol("(") || previous.isSymbol(".") || previous.isSymbol(":") ||
+				((previous.isSymbol("-") || previous.isSymbol("+")) && i == 1)
+			if !attached {
+				b.WriteByte(' ')
+			}
+		}
+		switch t.kind {
+		case stringToken:
+			b.WriteString("'" + strings.Replace(t.text, "'", "''", -1) + "'")
+		case identToken:
+			b.WriteString(`"` + t.text + `"`)
+		default:
+			b.WriteString(t.text)
+		}
+	}
+	return b.String()
+}
+
+// indexes returns the primary key and unique constraint declared on the column
+func (c columnDefinition) indexes() []Index {
+	var indexes []Index
+	if c.primaryKey {
+		indexes = append(indexes, Index{Columns: []string{c.Name}, Primary: true, Unique: true})
+	}
+	if c.unique {
+		indexes = append(indexes, Index{Columns: []string{c.Name}, Unique: true})
+	}
+	return indexes
+}
+
+// parseColumn parses the column definition of a create or alter table statement
+func parseColumn(dialect string, tokens []token) (columnDefinition, error) {
+	p := &parser{tokens: tokens}
+	fold := dialect == "postgresql"
+	var c columnDefinition
+	var err error
+	if c.Name, err = p.identifier(fold); err != nil {
+		return c, err
+	}
+
+	// the type is every word up to the first column constraint, ex: timestamp(3) with time zone
+	var words []string
+	var size []token
+	for !p.done() {
+		t := p.peek()
+		switch {
+		case t.kind == wordToken && columnStopKeywords[strings.ToLower(t.text)]:
+		case t.is("character") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].is("set"):
+		case t.kind == wordToken && columnModifiers[strings.ToLower(t.text)]:
+			c.Unsigned = c.Unsigned || p.next().is("unsigned")
+			continue
+		case t.kind == wordToken, t.kind == identToken && len(words) == 0:
+			words = append(words, strings.ToLower(p.next().text))
+			continue
+		case t.isSymbol("("):
+			group, err := p.group()
+			if err != nil {
+				return c, err
+			}
+			if size == nil {
+				size = group
+			}
+			continue
+		case t.isSymbol("[") || t.isSymbol("]"):
+			words = append(words, p.next().text)
+			continue
+		}
+		break
+	}
+	// sqlite is the only dialect allowing columns without a type, besides sql server computed columns,
+	// ex: [total] AS ([price]*[quantity]) PERSISTED, which are left without a type for type overrides
+	if len(words) == 0 && dialect != "sqlite" && !(dialect == "mssql" && p.peek().is("as")) {
+		return c, fmt.Errorf("column %s is missing a type", c.Name)
+	}
+	written := strings.Replace(strings.Join(words, " "), " [ ]", "[]", -1)
+	c.DatabaseType = normalizeType(dialect, written)
+	c.setSize(size)
+
+	notNull := notNullTypes[dialect][written]
+	for !p.done() {
+		switch {
+		case p.keyword("not", "null"):
+			notNull = true
+		case p.keyword("primary", "key"):
+			c.primaryKey = true
+		case p.keyword("unique"):
+			c.unique = true
+			p.keyword("key")
+		case p.keyword("key"):
+			// mysql treats a lone KEY as PRIMARY KEY
+			c.primaryKey = true
+		case p.keyword("references"):
+			key, err := references(p, fold)
+			if err != nil {
+				return c, err
+			}
+			key.Columns = []string{c.Name}
+			c.references = &key
+		case p.keyword("by", "default"):
+			// postgresql GENERATED BY DEFAULT AS IDENTITY, the column has no default expression
+		case p.keyword("default"):
+			if value := defaultExpression(p); !strings.EqualFold(value, "null") {
+				c.Default = &value
+			}
+		case p.keyword("identity"):
+			// sql server IDENTITY and postgresql GENERATED ... AS IDENTITY columns are never null
+			notNull = dialect == "mssql" || dialect == "postgresql" || notNull
+			c.AutoIncrement = true
+		case p.keyword("auto_increment"), p.keyword("autoincrement"):
+			c.AutoIncrement = true
+		case p.keyword("as"):
+			// GENERATED ALWAYS AS (expr) and the AS (expr) shorthand of mysql and sql server computed
+			// columns, the expression is skipped as a group on the next pass
+			c.Generated = c.Generated || p.peek().isSymbol("(")
+		case p.peek().isSymbol("("):
+			// skip check expressions and defaults so their keywords are not mistaken for constraints
+			if _, err := p.group(); err != nil {
+				return c, err
+			}
+		default:
+			p.next()
+		}
+	}
+	// primary keys are implicitly not null except for sqlite where only integer keys are
+	if c.primaryKey && (dialect != "sqlite" || c.DatabaseType == "integer") {
+		notNull = true
+	}
+	// sql server sets rowversion, also named timestamp, on every insert and update
+	if dialect == "mssql" && (c.DatabaseType == "rowversion" || c.DatabaseType == "timestamp") {
+		c.Generated = true
+	}
+	// serial types and sqlite integer primary keys, aliases of the rowid, are filled in on insert
+	if notNullTypes[dialect][written] || dialect == "sqlite" && c.primaryKey && c.DatabaseType == "integer" {
+		c.AutoIncrement = true
+	}
+	c.DatabaseNullable = "YES"
+	if notNull {
+		c.DatabaseNullable = "NO"
+	}
+	return c, nil
+}
+
+// indexColumns parses the parenthesized column list of an index, ex: (last_name, first_name(10) DESC).
+// Indexes on expressions are not indexes of columns and are reported as not ok.
+func indexColumns(p *parser, fold bool) ([]string, bool) {
+	inner, err := p.group()
+	if err != nil {
+		return nil, false
+	}
+	var names []string
+	for _, item := range splitList(inner) {
+		ip := &parser{tokens: item}
+		name, err := ip.identifier(fold)
+		if err != nil {
+			return nil, false
+		}
+		// mysql prefix lengths are the only parentheses allowed after the column name
+		if ip.peek().isSymbol("(") {
+			prefix, err := ip.group()
+			if err != nil || len(prefix) != 1 || prefix[0].kind != numberToken {
+				return nil, false
+			}
+		}
+		names = append(names, name)
+	}
+	return names, len(names) > 0
+}
+
+// references parses the referenced table and columns of a foreign key, ex: users (id). The columns
+// are optional when the primary key of the table is referenced.
+func references(p *parser, fold bool) (ForeignKey, error) {
+	var key ForeignKey
+	qualifier, table, err := p.qualifiedName(fold)
+	if err != nil {
+		return key, err
+	}
+	key.ReferencedSchema, key.ReferencedTable = qualifier, table
+	if p.peek().isSymbol("(") {
+		columns, ok := indexColumns(p, fold)
+		if !ok {
+			return key, fmt.Errorf("invalid column list referencing table %s", table)
+		}
+		key.ReferencedColumns = columns
+	}
+	return key, nil
+}
+
+// constraintForeignKey parses a foreign key element of a create or alter table statement,
+// ex: CONSTRAINT posts_author FOREIGN KEY (author_id) REFERENCES users (id)
+func constraintForeignKey(p *parser, fold bool) (ForeignKey, bool) {
+	var name string
+	if p.keyword("constraint") && !p.peek().is("foreign") {
+		var err error
+		if name, err = p.identifier(fold); err != nil {
+			return ForeignKey{}, false
+		}
+	}
+	if !p.keyword("foreign", "key") {
+		return ForeignKey{}, false
+	}
+	// mysql allows naming the index of the foreign key
+	if !p.peek().isSymbol("(") {
+		p.next()
+	}
+	columns, ok := indexColumns(p, fold)
+	if !ok || !p.keyword("references") {
+		return ForeignKey{}, false
+	}
+	key, err := references(p, fold)
+	if err != nil {
+		return ForeignKey{}, false
+	}
+	key.Name = name
+	key.Columns = columns
+	return key, true
+}
+
+// indexOptions are written between the kind of an index and its column list without being its name
+var indexOptions = map[string]bool{"clustered": true, "nonclustered": true, "nulls": true, "not": true, "distinct": true}
+
+// constraintIndex parses a primary key, unique constraint or index element of a create or alter table
+// statement. Foreign key, check and exclusion constraints are not indexes and are reported as not ok.
+func constraintIndex(p *parser, fold bool) (Index, bool) {
+	var i Index
+	if p.keyword("constraint") && !p.peek().is("primary") && !p.peek().is("unique") {
+		name, err := p.identifier(fold)
+		if err != nil {
+			return i, false
+		}
+		i.Name = name
+	}
+	switch {
+	case p.keyword("primary", "key"):
+		i.Primary, i.Unique = true, true
+	case p.keyword("unique"):
+		i.Unique = true
+		if !p.keyword("key") {
+			p.keyword("index")
+		}
+	case p.keyword("key"), p.keyword("index"):
+	case p.keyword("fulltext"), p.keyword("spatial"):
+		if !p.keyword("key") {
+			p.keyword("index")
+		}
+	default:
+		return i, false
+	}
+	// mysql names the index and its type and sql server the clustering before the columns
+	for !p.done() && !p.peek().isSymbol("(") {
+		t := p.next()
+		switch {
+		case t.is("using"):
+			p.next()
+		case t.kind == wordToken && indexOptions[strings.ToLower(t.text)]:
+		case i.Name == "" && t.kind == identToken:
+			i.Name = t.text
+		case i.Name == "" && t.kind == wordToken:
+			i.Name = t.text
+			if fold {
+				i.Name = strings.ToLower(t.text)
+			}
+		}
+	}
+	columns, ok := indexColumns(p, fold)
+	i.Columns = columns
+	return i, ok
+}
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// columnString describes the column the way it is compared in the tests,
+// ex: id int(10,0) not null auto
+func columnString(c Column) string {
+	s := c.Name + " " + c.DatabaseType
+	switch {
+	case c.Length != 0:
+		s += fmt.Sprintf("(%d)", c.Length)
+	case c.Precision != 0:
+		s += fmt.Sprintf("(%d,%d)", c.Precision, c.Scale)
+	}
+	if c.Unsigned {
+		s += " unsigned"
+	}
+	if c.DatabaseNullable == "NO" {
+		s += " not null"
+	}
+	if c.Default != nil {
+		s += " default " + *c.Default
+	}
+	if c.AutoIncrement {
+		s += " auto"
+	}
+	if c.Generated {
+		s += " generated"
+	}
+	return s
+}
+
+func TestStatements(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+		want    []string
+	}{
+		{
+			dialect: "postgresql",
+			sql:     "CREATE TABLE a (id int);\nCREATE TABLE b (id int)",
+			want:    []string{"CREATE TABLE a(id int)", "CREATE TABLE b(id int)"},
+		},
+		{
+			dialect: "postgresql",
+			sql:     "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql;\nCREATE TABLE a (id int);",
+			want:    []string{"CREATE FUNCTION f() RETURNS int AS ' SELECT 1; ' LANGUAGE sql", "CREATE TABLE a(id int)"},
+		},
+		{
+			dialect: "postgresql",
+			sql:     "DO $body$ BEGIN PERFORM 1; END $body$;\nCREATE TABLE a (id int);",
+			want:    []string{"DO ' BEGIN PERFORM 1; END '", "CREATE TABLE a(id int)"},
+		},
+		{
+			dialect: "postgresql",
+			sql:     "-- drop; the table\nCREATE TABLE a (/* id; */ id int, note text DEFAULT 'a;b');",
+			want:    []string{"CREATE TABLE a(id int, note text DEFAULT 'a;b')"},
+		},
+		{
+			dialect: "mysql",
+			sql:     "# comment; with a semicolon\nCREATE TABLE a (note text DEFAULT 'it\\'s;');",
+			want:    []string{"CREATE TABLE a(note text DEFAULT 'it''s;')"},
+		},
+		{
+			dialect: "mssql",
+			sql:     "CREATE TABLE [a;b] ([id] int)\nGO\nCREATE TABLE b ([id] int)\nGO\n",
+			want:    []string{`CREATE TABLE "a;b" ("id" int)`, `CREATE TABLE b("id" int)`},
+		},
+		{
+			dialect: "mssql",
+			sql:     "GO\nCREATE TABLE a (go int);\nGO",
+			want:    []string{"CREATE TABLE a(go int)"},
+		},
+	}
+	for _, test := range tests {
+		tokens, err := tokenize(test.dialect, test.sql)
+		if err != nil {
+			t.Errorf("%s %q: %s", test.dialect, test.sql, err)
+			continue
+		}
+		var got []string
+		for _, stmt := range statements(tokens) {
+			got = append(got, sqlText(stmt))
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("%s %q:\ngot  %q\nwant %q", test.dialect, test.sql, got, test.want)
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+		want    string
+	}{
+		{"postgresql", "CREATE TABLE a (id int);\n/* open", "line 2: unterminated comment"},
+		{"postgresql", "SELECT 'open", "line 1: unterminated quoted text"},
+		{"postgresql", "\n\nDO $$ BEGIN", "line 3: unterminated dollar quoted string"},
+		{"mssql", "CREATE TABLE [open (id int)", "line 1: unterminated quoted text"},
+	}
+	for _, test := range tests {
+		_, err := tokenize(test.dialect, test.sql)
+		if err == nil || err.Error() != test.want {
+			t.Errorf("%s %q: got error %v, want %s", test.dialect, test.sql, err, test.want)
+		}
+	}
+}
+
+func TestParseColumn(t *testing.T) {
+	tests := []struct {
+		dialect    string
+		definition string
+		want       string
+	}{
+		{"postgresql", "id serial PRIMARY KEY", "id serial not null auto"},
+		{"postgresql", "id bigint GENERATED ALWAYS AS IDENTITY", "id bigint not null auto"},
+		{"postgresql", "total numeric(10,2) GENERATED ALWAYS AS (price * 2) STORED", "total numeric(10,2) generated"},
+		{"postgresql", "name varchar(100) NOT NULL DEFAULT 'none'", "name varchar(100) not null default 'none'"},
+		{"postgresql", "created timestamp(3) with time zone DEFAULT now()", "created timestamp with time zone default now()"},
+		{"postgresql", "tags text[] NOT NULL", "tags text[] not null"},
+		{"postgresql", "state text CHECK (state IN ('a', 'b')) NOT NULL", "state text not null"},
+		{"mysql", "id int(10) unsigned NOT NULL AUTO_INCREMENT", "id int unsigned not null auto"},
+		{"mysql", "total int AS (qty * 2) VIRTUAL", "total int generated"},
+		{"mysql", "t2 int GENERATED ALWAYS AS (qty) STORED NOT NULL", "t2 int not null generated"},
+		{"mysql", "name varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL", "name varchar(20)"},
+		{"mariadb", "price decimal(10,2) NOT NULL DEFAULT 0.00", "price decimal(10,2) not null default 0.00"},
+		{"mssql", "[id] int IDENTITY(1,1) NOT NULL", "id int not null auto"},
+		{"mssql", "[v] AS ([id]*2)", "v  generated"},
+		{"mssql", "[p] AS ([id]*3) PERSISTED NOT NULL", "p  not null generated"},
+		{"mssql", "[rv] rowversion", "rv rowversion generated"},
+		{"mssql", "[body] nvarchar(max) NULL", "body nvarchar(-1)"},
+		{"sqlite", "id INTEGER PRIMARY KEY", "id integer not null auto"},
+		{"sqlite", "data", "data "},
+		{"vertica", "id int IDENTITY(1, 1)", "id int auto"},
+	}
+	for _, test := range tests {
+		tokens, err := tokenize(test.dialect, test.definition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := parseColumn(test.dialect, tokens)
+		if err != nil {
+			t.Errorf("%s %q: %s", test.dialect, test.definition, err)
+			continue
+		}
+		if got := columnString(c.Column); got != test.want {
+			t.Errorf("%s %q:\ngot  %q\nwant %q", test.dialect, test.definition, got, test.want)
+		}
+	}
+}
+
+func TestParseColumnErrors(t *testing.T) {
+	tests := []struct {
+		dialect    string
+		definition string
+		want       string
+	}{
+		{"postgresql", "id NOT NULL", "column id is missing a type"},
+		{"mysql", "total AS (qty * 2)", "column total is missing a type"},
+		{"mssql", "id NULL", "column id is missing a type"},
+	}
+	for _, test := range tests {
+		tokens, err := tokenize(test.dialect, test.definition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = parseColumn(test.dialect, tokens)
+		if err == nil || err.Error() != test.want {
+			t.Errorf("%s %q: got error %v, want %s", test.dialect, test.definition, err, test.want)
+		}
+	}
+}
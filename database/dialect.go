@@ -0,0 +1,119 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// dialectTypeMaps holds the type map of every dialect that is read from the information schema
+var dialectTypeMaps = map[string]map[string]ColumnDefinition{
+	"mariadb":    mariaDBTypeMap,
+	"mysql":      mySQLTypeMap,
+	"postgresql": postgresTypeMap,
+	"vertica":    verticaTypeMap,
+	"mssql":      mssqlTypeMap,
+}
+
+// dialectAliases maps the type names accepted in DDL statements to the names reported
+// by the information schema of each dialect
+var dialectAliases = map[string]map[string]string{
+	"mariadb": mySQLAliases,
+	"mysql":   mySQLAliases,
+	"postgresql": map[string]string{
+		"int8":    "bigint",
+		"serial8": "bigserial",
+		"bool":    "boolean",
+		"float4":  "real",
+		"varbit":  "bit varying",
+	},
+	"vertica": map[string]string{
+		"integer":                  "int",
+		"int8":                     "bigint",
+		"bool":                     "boolean",
+		"decimal":                  "numeric",
+		"money":                    "numeric",
+		"timestamptz":              "timestamp",
+		"timestamp with time zone": "timestamp",
+		"timetz":                   "time",
+		"time with time zone":      "time",
+		"long varbinary":           "varbinary",
+	},
+	"mssql": map[string]string{
+		"integer":                    "int",
+		"dec":                        "decimal",
+		"double precision":           "float",
+		"character":                  "char",
+		"character varying":          "varchar",
+		"char varying":               "varchar",
+		"national character":         "nchar",
+		"national char":              "nchar",
+		"national character varying": "nvarchar",
+		"national char varying":      "nvarchar",
+		"national text":              "ntext",
+	},
+}
+
+var mySQLAliases = map[string]string{
+	"bool":              "tinyint",
+	"boolean":           "tinyint",
+	"integer":           "int",
+	"int1":              "tinyint",
+	"int2":              "smallint",
+	"int3":              "mediumint",
+	"int4":              "int",
+	"int8":              "bigint",
+	"middleint":         "mediumint",
+	"serial":            "bigint",
+	"dec":               "decimal",
+	"fixed":             "decimal",
+	"numeric":           "decimal",
+	"real":              "double",
+	"double precision":  "double",
+	"float4":            "float",
+	"float8":            "double",
+	"character":         "char",
+	"character varying": "varchar",
+	"long":              "mediumtext",
+	"long varchar":      "mediumtext",
+	"long varbinary":    "mediumblob",
+}
+
+// Dialects returns the names of the supported sql dialects
+func Dialects() []string {
+	names := []string{"sqlite"}
+	for name := range dialectTypeMaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validDialect(dialect string) error {
+	for _, d := range Dialects() {
+		if d == dialect {
+			return nil
+		}
+	}
+	return fmt.Errorf("Unsupported dialect %s, expected one of %s", dialect, strings.Join(Dialects(), ", "))
+}
+
+// normalizeType converts a type name as written in a DDL statement to the name used
+// by the dialect's information schema
+func normalizeType(dialect, databaseType string) string {
+	databaseType = strings.ToLower(databaseType)
+	if alias, ok := dialectAliases[dialect][databaseType]; ok {
+		return alias
+	}
+	return databaseType
+}
+
+// definition returns the column definition for the database type of the dialect
+func definition(dialect, databaseType string) (ColumnDefinition, bool) {
+	if dialect == "sqlite" {
+		_, d := sqliteDefinition(databaseType)
+		return d, true
+	}
+	d, ok := dialectTypeMaps[dialect][databaseType]
+	return d, ok
+}
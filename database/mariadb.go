@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql" // mysql driver for mariadb support
+)
+
+// MariaDB and its methods builds the common table and column structure for formatting
+type MariaDB struct {
+	Connection
+}
+
+const (
+	mariaDBColumnQuery     = "SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, COLUMN_DEFAULT, COLUMN_TYPE, EXTRA FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND table_name = ? ORDER BY ORDINAL_POSITION"
+	mariaDBTableQuery      = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	mariaDBViewQuery       = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE IN ('VIEW', 'SYSTEM VIEW') ORDER BY TABLE_NAME"
+	mariaDBTypeQuery       = "SELECT TABLE_TYPE FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	mariaDBIndexQuery      = "SELECT INDEX_NAME, CASE WHEN INDEX_NAME = 'PRIMARY' THEN 1 ELSE 0 END, 1 - NON_UNIQUE, COLUMN_NAME FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME IS NOT NULL ORDER BY INDEX_NAME, SEQ_IN_INDEX"
+	mariaDBForeignKeyQuery = "SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME, REFERENCED_TABLE_SCHEMA FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION"
+)
+
+// Build retrieves the table and column schema information for the table and database name to be generated
+// it then parses and converts the database specific types into the correct go types
+func (m MariaDB) Build(ctx context.Context, database, table string) (*Table, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	t := Table{Name: table, Dialect: "mariadb"}
+	if t.Type, err = queryType(ctx, db, mariaDBTypeQuery, database, table); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, mariaDBColumnQuery, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var columnType, extra string
+		c, err := scanColumn(rows, &columnType, &extra)
+		if err != nil {
+			return nil, err
+		}
+		// the column type holds the attributes of the type, ex: int(10) unsigned
+		c.Unsigned = strings.Contains(columnType, "unsigned")
+		c.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		c.Generated = generatedExtra(extra)
+		// mariadb reports columns without a default as NULL, literal defaults quoted and expressions as
+		// written, ex: 'active' and current_timestamp(). Versions before 10.2.7 report literals unquoted.
+		switch {
+		case c.Default == nil || strings.HasPrefix(*c.Default, "'") || currentTime(*c.Default) || strings.Contains(*c.Default, "("):
+		case *c.Default == "NULL":
+			c.Default = nil
+		default:
+			c.Default = quotedDefault(c)
+		}
+		// unrecognized types are left without a definition for type overrides
+		c.Definition = mariaDBTypeMap[c.DatabaseType]
+		t.Columns = append(t.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("No rows returned from the information schema for TABLE_SCHEMA %s and TABLE_NAME %s", database, table)
+	}
+	if t.Indexes, err = queryIndexes(ctx, db, mariaDBIndexQuery, database, table); err != nil {
+		return nil, err
+	}
+	if t.ForeignKeys, err = queryForeignKeys(ctx, db, mariaDBForeignKeyQuery, database, table); err != nil {
+		return nil, err
+	}
+	t.markKeys()
+	return &t, nil
+}
+
+// ListTables returns the names of the tables in the database
+func (m MariaDB) ListTables(ctx context.Context, database string) ([]string, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, mariaDBTableQuery, database)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// ListViews returns the names of the views in the database
+func (m MariaDB) ListViews(ctx context.Context, database string) ([]string, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, mariaDBViewQuery, database)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// open connects to the database, the password is read from the option files when none is set
+func (m MariaDB) open(ctx context.Context, database string) (*sql.DB, error) {
+	conn, err := m.withOptionFile("client", "client-mariadb")
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := conn.mysqlDSN(database)
+	if err != nil {
+		return nil, err
+	}
+	return open(ctx, "mysql", dsn)
+}
+
+var mariaDBTypeMap = map[string]ColumnDefinition{
+	// integer fields
+	"tinyint":   ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int":       ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"smallint":  ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"mediumint": ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"bigint":    ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	// string fields
+	"char":       ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"set":        ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"enum":       ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"varchar":    ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"tinytext":   ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"longtext":   ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"mediumtext": ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"text":       ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	// time fields
+	"date":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"datetime":  ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"time":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"year":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamp": ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	// float fields
+	"decimal": ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"double":  ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float":   ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	// binary fields
+	"binary":     ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"blob":       ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"tinyblob":   ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"longblob":   ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"mediumblob": ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"varbinary":  ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// migrationFile matches versioned migration file names, ex: 0001_create_users.up.sql or 20180101120000_create_users.sql
+var migrationFile = regexp.MustCompile(`^([0-9]+)[_\-.]?.*?(\.(up|down))?\.sql$`)
+
+// migration is a single migration file of a migration directory
+type migration struct {
+	version string
+	path    string
+}
+
+// LoadMigrations replays the up migrations of the directory in version order into a new schema.
+// golang-migrate style directories with separate .up.sql and .down.sql files are supported, as are
+// goose and dbmate style files holding both directions separated by annotations.
+func LoadMigrations(dialect, dir string) (*Schema, error) {
+	s, err := NewSchema(dialect)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := readMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range migrations {
+		b, err := ioutil.ReadFile(m.path)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Apply(upMigration(string(b))); err != nil {
+			return nil, fmt.Errorf("%s: %s", m.path, err)
+		}
+	}
+	return s, nil
+}
+
+// readMigrations lists the up migrations of the directory ordered by version
+func readMigrations(dir string) ([]migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []migration
+	versions := map[string]string{}
+	for _, f := range files {
+		match := migrationFile.FindStringSubmatch(f.Name())
+		if f.IsDir() || match == nil || match[3] == "down" {
+			continue
+		}
+		// versions are compared numerically, leading zeros are not significant
+		version := strings.TrimLeft(match[1], "0")
+		if previous, ok := versions[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %s in %s and %s", match[1], previous, f.Name())
+		}
+		versions[version] = f.Name()
+		migrations = append(migrations, migration{version: version, path: filepath.Join(dir, f.Name())})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		a, b := migrations[i].version, migrations[j].version
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	return migrations, nil
+}
+
+// upMigration returns the up statements of a migration. Files annotated with goose (-- +goose Up)
+// or dbmate (-- migrate:up) directions only keep their up sections, other files are kept as a whole.
+func upMigration(sql string) string {
+	var up []string
+	annotated := false
+	include := false
+	for _, line := range strings.Split(sql, "\n") {
+		switch strings.ToLower(strings.Join(strings.Fields(line), " ")) {
+		case "-- +goose up", "-- migrate:up":
+			annotated, include = true, true
+			line = ""
+		case "-- +goose down", "-- migrate:down":
+			annotated, include = true, false
+		}
+		if include {
+			up = append(up, line)
+		} else {
+			// keep line numbers of errors aligned with the file
+			up = append(up, "")
+		}
+	}
+	if !annotated {
+		return sql
+	}
+	return strings.Join(up, "\n")
+}
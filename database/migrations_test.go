@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// migrationDir writes the files to a new temporary directory
+func migrationDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, sql := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(sql), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestUpMigration(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "golang-migrate",
+			sql:  "CREATE TABLE a (id int);\n",
+			want: "CREATE TABLE a (id int);\n",
+		},
+		{
+			name: "goose",
+			sql:  "-- +goose Up\nCREATE TABLE a (id int);\n-- +goose Down\nDROP TABLE a;\n",
+			want: "\nCREATE TABLE a (id int);\n\n\n",
+		},
+		{
+			name: "goose statement blocks",
+			sql:  "-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE a (id int);\n-- +goose StatementEnd\n-- +goose Down\nDROP TABLE a;",
+			want: "\n-- +goose StatementBegin\nCREATE TABLE a (id int);\n-- +goose StatementEnd\n\n",
+		},
+		{
+			name: "dbmate",
+			sql:  "-- migrate:up\nCREATE TABLE a (id int);\n\n-- migrate:down\nDROP TABLE a;\n",
+			want: "\nCREATE TABLE a (id int);\n\n\n\n",
+		},
+		{
+			name: "down first",
+			sql:  "--   migrate:down\nDROP TABLE a;\n--migrate:up\n-- MIGRATE:UP\nCREATE TABLE a (id int);",
+			want: "\n\n\n\nCREATE TABLE a (id int);",
+		},
+	}
+	for _, test := range tests {
+		if got := upMigration(test.sql); got != test.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+		err   string
+	}{
+		{
+			name:  "golang-migrate",
+			files: []string{"2_b.up.sql", "2_b.down.sql", "1_a.up.sql", "1_a.down.sql", "10_c.up.sql", "10_c.down.sql"},
+			want:  "1_a.up.sql, 2_b.up.sql, 10_c.up.sql",
+		},
+		{
+			name:  "leading zeros",
+			files: []string{"0010_c.sql", "0002_b.sql", "9_a.sql"},
+			want:  "0002_b.sql, 9_a.sql, 0010_c.sql",
+		},
+		{
+			name:  "timestamps",
+			files: []string{"20180102000000_b.sql", "20180101120000_a.sql", "README.md", "seed.sql"},
+			want:  "20180101120000_a.sql, 20180102000000_b.sql",
+		},
+		{
+			name:  "duplicate versions",
+			files: []string{"1_a.up.sql", "01_b.up.sql"},
+			err:   "duplicate migration version",
+		},
+		{
+			name:  "duplicate versions of different styles",
+			files: []string{"3_a.sql", "3_a.up.sql"},
+			err:   "duplicate migration version 3",
+		},
+	}
+	for _, test := range tests {
+		files := map[string]string{}
+		for _, name := range test.files {
+			files[name] = ""
+		}
+		dir := migrationDir(t, files)
+		migrations, err := readMigrations(dir)
+		os.RemoveAll(dir)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %s", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		var names []string
+		for _, m := range migrations {
+			names = append(names, filepath.Base(m.path))
+		}
+		if got := strings.Join(names, ", "); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		files   map[string]string
+		want    string
+		err     string
+	}{
+		{
+			name:    "golang-migrate",
+			dialect: "postgresql",
+			files: map[string]string{
+				"1_users.up.sql":     "CREATE TABLE users (id serial PRIMARY KEY, name text);",
+				"1_users.down.sql":   "DROP TABLE users;",
+				"2_email.up.sql":     "ALTER TABLE users ADD COLUMN email text NOT NULL;",
+				"2_email.down.sql":   "ALTER TABLE users DROP COLUMN email;",
+				"10_rename.up.sql":   "ALTER TABLE users RENAME COLUMN name TO full_name;",
+				"10_rename.down.sql": "ALTER TABLE users RENAME COLUMN full_name TO name;",
+			},
+			want: `id serial not null auto
+full_name text
+email text not null
+primary users_pkey (id)`,
+		},
+		{
+			name:    "goose",
+			dialect: "mysql",
+			files: map[string]string{
+				"20180101000000_users.sql": "-- +goose Up\nCREATE TABLE users (id int AUTO_INCREMENT PRIMARY KEY, name varchar(10));\n-- +goose Down\nDROP TABLE users;\n",
+				"20180102000000_name.sql":  "-- +goose Up\nALTER TABLE users MODIFY name varchar(50) NOT NULL;\n-- +goose Down\nALTER TABLE users MODIFY name varchar(10);\n",
+			},
+			want: `id int not null auto
+name varchar(50) not null
+primary PRIMARY (id)`,
+		},
+		{
+			name:    "dbmate",
+			dialect: "sqlite",
+			files: map[string]string{
+				"20180101000000_users.sql": "-- migrate:up\nCREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);\n\n-- migrate:down\nDROP TABLE users;\n",
+				"20180102000000_drop.sql":  "-- migrate:up\nALTER TABLE users DROP COLUMN name;\n\n-- migrate:down\nALTER TABLE users ADD COLUMN name TEXT;\n",
+			},
+			want: `id integer not null auto
+primary users_pkey (id)`,
+		},
+		{
+			name:    "errors name the file and line",
+			dialect: "postgresql",
+			files: map[string]string{
+				"1_users.sql": "-- +goose Up\nCREATE TABLE users (id int);\n-- +goose Down\nDROP TABLE users;\n",
+				"2_bad.sql":   "-- +goose Up\n\nALTER TABLE accounts ADD COLUMN a int;\n",
+			},
+			err: "2_bad.sql: line 3: table accounts does not exist",
+		},
+	}
+	for _, test := range tests {
+		dir := migrationDir(t, test.files)
+		s, err := LoadMigrations(test.dialect, dir)
+		os.RemoveAll(dir)
+		if test.err != "" {
+			if err == nil || !strings.HasSuffix(err.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %s", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		table, err := s.Build(context.Background(), "", "users")
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if got := tableString(table); got != test.want {
+			t.Errorf("%s:\ngot\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/denisenkom/go-mssqldb" // sql server driver
+)
+
+// MSSQL and its methods builds the common table and column structure for formatting
+type MSSQL struct {
+	Connection
+	// Schema is used for table names that are not schema qualified, defaults to dbo
+	Schema string
+}
+
+const (
+	mssqlColumnQuery     = "SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, COLUMN_DEFAULT, COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsIdentity'), COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsComputed') FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_CATALOG = @p1 AND TABLE_SCHEMA = @p2 AND TABLE_NAME = @p3 ORDER BY ORDINAL_POSITION"
+	mssqlTableQuery      = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_CATALOG = @p1 AND TABLE_SCHEMA = @p2 AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	mssqlViewQuery       = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_CATALOG = @p1 AND TABLE_SCHEMA = @p2 AND TABLE_TYPE = 'VIEW' ORDER BY TABLE_NAME"
+	mssqlTypeQuery       = "SELECT TABLE_TYPE FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_CATALOG = @p1 AND TABLE_SCHEMA = @p2 AND TABLE_NAME = @p3"
+	mssqlIndexQuery      = "SELECT i.name, CAST(i.is_primary_key AS int), CAST(i.is_unique AS int), c.name FROM sys.indexes i JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id JOIN sys.objects o ON o.object_id = i.object_id JOIN sys.schemas s ON s.schema_id = o.schema_id WHERE s.name = @p1 AND o.name = @p2 AND ic.is_included_column = 0 ORDER BY i.name, ic.key_ordinal"
+	mssqlForeignKeyQuery = "SELECT f.name, c.name, r.name, rc.name, SCHEMA_NAME(r.schema_id) FROM sys.foreign_keys f JOIN sys.foreign_key_columns fc ON fc.constraint_object_id = f.object_id JOIN sys.columns c ON c.object_id = fc.parent_object_id AND c.column_id = fc.parent_column_id JOIN sys.objects r ON r.object_id = fc.referenced_object_id JOIN sys.columns rc ON rc.object_id = fc.referenced_object_id AND rc.column_id = fc.referenced_column_id JOIN sys.objects o ON o.object_id = f.parent_object_id JOIN sys.schemas s ON s.schema_id = o.schema_id WHERE s.name = @p1 AND o.name = @p2 ORDER BY f.name, fc.constraint_column_id"
+)
+
+// Build retrieves the table and column schema information for the table and database name to be generated
+// it then parses and converts the database specific types into the correct go types
+func (m MSSQL) Build(ctx context.Context, database, table string) (*Table, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	schema, name := m.schema(table)
+	t := Table{Name: name, Schema: schema, Dialect: "mssql"}
+	if t.Type, err = queryType(ctx, db, mssqlTypeQuery, database, schema, name); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, mssqlColumnQuery, database, schema, name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var identity, computed sql.NullInt64
+		c, err := scanColumn(rows, &identity, &computed)
+		if err != nil {
+			return nil, err
+		}
+		c.AutoIncrement = identity.Int64 == 1
+		// rowversion, also named timestamp, is set by the server on every insert and update
+		c.Generated = computed.Int64 == 1 || c.DatabaseType == "rowversion" || c.DatabaseType == "timestamp"
+		// unrecognized types are left without a definition for type overrides
+		c.Definition = mssqlTypeMap[c.DatabaseType]
+		t.Columns = append(t.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("No rows returned from the information schema for TABLE_CATALOG %s, TABLE_SCHEMA %s and TABLE_NAME %s", database, schema, name)
+	}
+	if t.Indexes, err = queryIndexes(ctx, db, mssqlIndexQuery, schema, name); err != nil {
+		return nil, err
+	}
+	if t.ForeignKeys, err = queryForeignKeys(ctx, db, mssqlForeignKeyQuery, schema, name); err != nil {
+		return nil, err
+	}
+	t.markKeys()
+	return &t, nil
+}
+
+// ListTables returns the names of the tables in the configured schema of the database
+func (m MSSQL) ListTables(ctx context.Context, database string) ([]string, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	schema, _ := m.schema("")
+	rows, err := db.QueryContext(ctx, mssqlTableQuery, database, schema)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// ListViews returns the names of the views in the configured schema of the database
+func (m MSSQL) ListViews(ctx context.Context, database string) ([]string, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	schema, _ := m.schema("")
+	rows, err := db.QueryContext(ctx, mssqlViewQuery, database, schema)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// schema splits a schema qualified table name, falling back to the configured schema
+func (m *MSSQL) schema(table string) (string, string) {
+	if i := strings.Index(table, "."); i >= 0 {
+		return table[:i], table[i+1:]
+	}
+	if m.Schema == "" {
+		return "dbo", table
+	}
+	return m.Schema, table
+}
+
+// open connects to the database
+func (m MSSQL) open(ctx context.Context, database string) (*sql.DB, error) {
+	dsn, err := m.mssqlDSN(database)
+	if err != nil {
+		return nil, err
+	}
+	return open(ctx, "sqlserver", dsn)
+}
+
+var mssqlTypeMap = map[string]ColumnDefinition{
+	// integer fields
+	"tinyint":  ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"smallint": ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int":      ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"bigint":   ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	// string fields
+	"char":     ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"varchar":  ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"text":     ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"nchar":    ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"nvarchar": ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"ntext":    ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"sysname":  ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"xml":      ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	// time fields
+	"date":           ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"datetime":       ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"datetime2":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"datetimeoffset": ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"smalldatetime":  ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"time":           ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	// float fields
+	"decimal":    ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"numeric":    ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"money":      ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"smallmoney": ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float":      ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"real":       ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	// binary fields
+	"binary":      ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"varbinary":   ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"image":       ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"rowversion":  ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"timestamp":   ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"hierarchyid": ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"geography":   ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"geometry":    ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	// bool
+	"bit": ColumnDefinition{GoType: "bool", GureguType: "null.Bool", SQLType: "sql.NullBool"},
+	// identifiers
+	"uniqueidentifier": ColumnDefinition{GoType: "mssql.UniqueIdentifier", GureguType: "*mssql.UniqueIdentifier", SQLType: "*mssql.UniqueIdentifier", Import: "github.com/denisenkom/go-mssqldb"},
+	// variant
+	"sql_variant": ColumnDefinition{GoType: "interface{}", GureguType: "interface{}", SQLType: "interface{}"},
+}
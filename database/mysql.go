@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql" // mysql driver for mariadb support
+)
+
+// MySQL and its methods builds the common table and column structure for formatting
+type MySQL struct {
+	Connection
+}
+
+const (
+	mySQLColumnQuery     = "SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, COLUMN_DEFAULT, COLUMN_TYPE, EXTRA FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND table_name = ? ORDER BY ORDINAL_POSITION"
+	mySQLTableQuery      = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	mySQLViewQuery       = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE IN ('VIEW', 'SYSTEM VIEW') ORDER BY TABLE_NAME"
+	mySQLTypeQuery       = "SELECT TABLE_TYPE FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	mySQLIndexQuery      = "SELECT INDEX_NAME, CASE WHEN INDEX_NAME = 'PRIMARY' THEN 1 ELSE 0 END, 1 - NON_UNIQUE, COLUMN_NAME FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME IS NOT NULL ORDER BY INDEX_NAME, SEQ_IN_INDEX"
+	mySQLForeignKeyQuery = "SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME, REFERENCED_TABLE_SCHEMA FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION"
+)
+
+// generatedExtra reports whether the extra column marks a generated column, ex: VIRTUAL GENERATED
+// and STORED GENERATED, or PERSISTENT in mariadb before 10.2
+func generatedExtra(extra string) bool {
+	extra = strings.ToUpper(extra)
+	return strings.Contains(extra, "VIRTUAL GENERATED") || strings.Contains(extra, "STORED GENERATED") || strings.Contains(extra, "PERSISTENT")
+}
+
+// Build retrieves the table and column schema information for the table and database name to be generated
+// it then parses and converts the database specific types into the correct go types
+func (m MySQL) Build(ctx context.Context, database, table string) (*Table, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	t := Table{Name: table, Dialect: "mysql"}
+	if t.Type, err = queryType(ctx, db, mySQLTypeQuery, database, table); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, mySQLColumnQuery, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var columnType, extra string
+		c, err := scanColumn(rows, &columnType, &extra)
+		if err != nil {
+			return nil, err
+		}
+		// the column type holds the attributes of the type, ex: int(10) unsigned
+		c.Unsigned = strings.Contains(columnType, "unsigned")
+		c.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		c.Generated = generatedExtra(extra)
+		// mysql reports literal defaults unquoted and expressions marked as generated in the extra
+		// column, ex: active for DEFAULT 'active' and (uuid()) for DEFAULT (uuid())
+		if c.Default != nil && !strings.Contains(extra, "DEFAULT_GENERATED") && !currentTime(*c.Default) {
+			c.Default = quotedDefault(c)
+		}
+		// unrecognized types are left without a definition for type overrides
+		c.Definition = mySQLTypeMap[c.DatabaseType]
+		t.Columns = append(t.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("No rows returned from the information schema for TABLE_SCHEMA %s and TABLE_NAME %s", database, table)
+	}
+	if t.Indexes, err = queryIndexes(ctx, db, mySQLIndexQuery, database, table); err != nil {
+		return nil, err
+	}
+	if t.ForeignKeys, err = queryForeignKeys(ctx, db, mySQLForeignKeyQuery, database, table); err != nil {
+		return nil, err
+	}
+	t.markKeys()
+	return &t, nil
+}
+
+// ListTables returns the names of the tables in the database
+func (m MySQL) ListTables(ctx context.Context, database string) ([]string, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, mySQLTableQuery, database)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// ListViews returns the names of the views in the database
+func (m MySQL) ListViews(ctx context.Context, database string) ([]string, error) {
+	db, err := m.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, mySQLViewQuery, database)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// open connects to the database, the password is read from the option files when none is set
+func (m MySQL) open(ctx context.Context, database string) (*sql.DB, error) {
+	conn, err := m.withOptionFile("client")
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := conn.mysqlDSN(database)
+	if err != nil {
+		return nil, err
+	}
+	return open(ctx, "mysql", dsn)
+}
+
+// currentTimeFunctions are the defaults of temporal columns that mysql reports without the
+// DEFAULT_GENERATED extra before mysql 8
+var currentTimeFunctions = []string{"current_timestamp", "current_date", "current_time", "localtime", "now("}
+
+// currentTime reports whether the default is the current date or time
+func currentTime(value string) bool {
+	value = strings.ToLower(value)
+	for _, f := range currentTimeFunctions {
+		if strings.HasPrefix(value, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedDefault returns the default of the column reported without quotes as a literal, numeric
+// columns, the columns with a numeric precision, keep their default as a number
+func quotedDefault(c Column) *string {
+	value := *c.Default
+	if c.Precision == 0 {
+		value = "'" + strings.Replace(value, "'", "''", -1) + "'"
+	}
+	return &value
+}
+
+var mySQLTypeMap = map[string]ColumnDefinition{
+	// integer fields
+	"tinyint":   ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int":       ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"smallint":  ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"mediumint": ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"bigint":    ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	// string fields
+	"char":       ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"set":        ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"enum":       ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"varchar":    ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"tinytext":   ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"longtext":   ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"mediumtext": ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"text":       ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	// time fields
+	"date":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"datetime":  ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"time":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"year":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamp": ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	// float fields
+	"decimal": ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"double":  ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float":   ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	// binary fields
+	"binary":     ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"blob":       ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"tinyblob":   ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"longblob":   ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"mediumblob": ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"varbinary":  ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+}
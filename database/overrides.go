@@ -0,0 +1,131 @@
+package database
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// TypeOverride replaces the column definition of the columns it matches. Columns are matched by their
+// database type, by a table.column or schema.table.column name or by a column name pattern such as
+// *_uuid, or by both a name and a type.
+type TypeOverride struct {
+	DatabaseType string `yaml:"database_type"`
+	Column       string `yaml:"column"`
+	GoType       string `yaml:"go_type"`
+	// NullableType is used for nullable columns unless the guregu or sql nullable type is set,
+	// the go type is used when none of them is set
+	NullableType string `yaml:"nullable_type"`
+	GureguType   string `yaml:"guregu_type"`
+	SQLType      string `yaml:"sql_type"`
+	Import       string `yaml:"import"`
+}
+
+// TypeOverrides are the type overrides of a type mapping file
+type TypeOverrides []TypeOverride
+
+// LoadTypeOverrides reads the type overrides of a yaml file, ex:
+//
+//	types:
+//	- database_type: uuid
+//	  go_type: uuid.UUID
+//	  nullable_type: uuid.NullUUID
+//	  import: github.com/google/uuid
+//	- column: users.tags
+//	  go_type: pq.StringArray
+//	  import: github.com/lib/pq
+func LoadTypeOverrides(file string) (TypeOverrides, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Types TypeOverrides `yaml:"types"`
+	}
+	if err := yaml.UnmarshalStrict(b, &config); err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	if err := config.Types.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	return config.Types, nil
+}
+
+// Validate reports the first override without a go type or a valid way to match columns
+func (o TypeOverrides) Validate() error {
+	for i, override := range o {
+		if override.GoType == "" {
+			return fmt.Errorf("type override %d is missing a go_type", i+1)
+		}
+		if override.DatabaseType == "" && override.Column == "" {
+			return fmt.Errorf("type override %d of %s needs a database_type or a column to match", i+1, override.GoType)
+		}
+		if _, err := path.Match(override.Column, ""); err != nil {
+			return fmt.Errorf("type override %d has an invalid column pattern %s: %s", i+1, override.Column, err)
+		}
+	}
+	return nil
+}
+
+// Apply replaces the definitions of the columns of the table matched by an override. The most specific
+// override wins, a schema.table.column name before a table.column name before a column name pattern
+// before a database type, and the first override of the file wins between equally specific overrides.
+func (o TypeOverrides) Apply(t *Table) {
+	for i := range t.Columns {
+		c := &t.Columns[i]
+		best := -1
+		for n, override := range o {
+			if rank := override.match(t, *c); rank > 0 && (best < 0 || rank > o[best].match(t, *c)) {
+				best = n
+			}
+		}
+		if best >= 0 {
+			c.Definition = o[best].definition()
+		}
+	}
+}
+
+// match ranks how specifically the override matches the column of the table, 0 when it does not match
+func (o TypeOverride) match(t *Table, c Column) int {
+	if o.DatabaseType != "" && !strings.EqualFold(o.DatabaseType, c.DatabaseType) && !strings.EqualFold(o.DatabaseType, c.FullType()) {
+		return 0
+	}
+	if o.Column == "" {
+		return 1
+	}
+	pattern, name := o.Column, strings.ToLower(c.Name)
+	dots := strings.Count(pattern, ".")
+	switch {
+	case dots == 1:
+		name = strings.ToLower(t.Name) + "." + name
+	case dots == 2 && t.Schema != "":
+		name = strings.ToLower(t.Schema) + "." + strings.ToLower(t.Name) + "." + name
+	case dots > 1:
+		return 0
+	}
+	if matched, _ := path.Match(strings.ToLower(pattern), name); !matched {
+		return 0
+	}
+	if dots > 0 && !strings.ContainsAny(pattern, "*?[") {
+		return 2 + dots
+	}
+	return 2
+}
+
+func (o TypeOverride) definition() ColumnDefinition {
+	d := ColumnDefinition{GoType: o.GoType, GureguType: o.GureguType, SQLType: o.SQLType, Import: o.Import}
+	nullable := o.NullableType
+	if nullable == "" {
+		nullable = o.GoType
+	}
+	if d.GureguType == "" {
+		d.GureguType = nullable
+	}
+	if d.SQLType == "" {
+		d.SQLType = nullable
+	}
+	return d
+}
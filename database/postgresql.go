@@ -0,0 +1,236 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq" // postgres driver
+)
+
+// PostgreSQL and its methods builds the common table and column structure for formatting
+type PostgreSQL struct {
+	Connection
+	// Schemas are the schemas tables are listed from and unqualified table names are resolved
+	// against in order, defaults to the search_path of the connection
+	Schemas []string
+}
+
+const (
+	postgresColumnQuery     = "SELECT column_name, data_type, is_nullable, character_maximum_length, numeric_precision, numeric_scale, column_default, is_identity, is_generated FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position"
+	postgresTableQuery      = "SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = $1 AND table_type = 'BASE TABLE' ORDER BY table_name"
+	postgresViewQuery       = "SELECT table_name FROM INFORMATION_SCHEMA.VIEWS WHERE table_schema = $1 UNION SELECT matviewname FROM pg_matviews WHERE schemaname = $1 ORDER BY 1"
+	postgresTypeQuery       = "SELECT CASE c.relkind WHEN 'v' THEN 'VIEW' WHEN 'm' THEN 'MATERIALIZED VIEW' ELSE 'BASE TABLE' END FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relname = $2"
+	postgresIndexQuery      = "SELECT i.relname, CASE WHEN x.indisprimary THEN 1 ELSE 0 END, CASE WHEN x.indisunique THEN 1 ELSE 0 END, a.attname FROM pg_index x JOIN pg_class c ON c.oid = x.indrelid JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(x.indkey) WHERE n.nspname = $1 AND c.relname = $2 ORDER BY i.relname, array_position(x.indkey::int2[], a.attnum)"
+	postgresForeignKeyQuery = "SELECT f.conname, a.attname, r.relname, ra.attname, rn.nspname FROM pg_constraint f JOIN pg_class c ON c.oid = f.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_class r ON r.oid = f.confrelid JOIN pg_namespace rn ON rn.oid = r.relnamespace CROSS JOIN LATERAL unnest(f.conkey, f.confkey) WITH ORDINALITY AS k(attnum, refattnum, position) JOIN pg_attribute a ON a.attrelid = f.conrelid AND a.attnum = k.attnum JOIN pg_attribute ra ON ra.attrelid = f.confrelid AND ra.attnum = k.refattnum WHERE f.contype = 'f' AND n.nspname = $1 AND c.relname = $2 ORDER BY f.conname, k.position"
+	// materialized views are not part of the information schema
+	postgresMaterializedColumnQuery = "SELECT a.attname, format_type(a.atttypid, a.atttypmod), CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END, NULL::int, NULL::int, NULL::int, NULL, 'NO', 'NEVER' FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum"
+	// current_schemas lists the existing schemas of the search_path in order
+	postgresSearchPathQuery = "SELECT unnest(current_schemas(false))"
+	// postgresResolveQuery returns the first schema of the list holding the table
+	postgresResolveQuery = "SELECT n.nspname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'f') AND n.nspname = ANY($2) ORDER BY array_position($2, n.nspname) LIMIT 1"
+)
+
+// Build retrieves the table and column schema information for the table and database name to be generated
+// it then parses and converts the database specific types into the correct go types. Table names that are
+// not schema qualified are looked up in the schemas, or the search_path, in order.
+func (p PostgreSQL) Build(ctx context.Context, database, table string) (*Table, error) {
+	db, err := p.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	schema, name, err := p.resolve(ctx, db, table)
+	if err != nil {
+		return nil, err
+	}
+	t := Table{Name: name, Schema: schema, Dialect: "postgresql"}
+	if t.Type, err = queryType(ctx, db, postgresTypeQuery, schema, name); err != nil {
+		return nil, err
+	}
+	var rows *sql.Rows
+	if t.Type == MaterializedView {
+		rows, err = db.QueryContext(ctx, postgresMaterializedColumnQuery, schema, name)
+	} else {
+		rows, err = db.QueryContext(ctx, postgresColumnQuery, schema, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var identity, generated string
+		c, err := scanColumn(rows, &identity, &generated)
+		if err != nil {
+			return nil, err
+		}
+		// serial columns default to the next value of their sequence
+		c.AutoIncrement = identity == "YES" || c.Default != nil && strings.HasPrefix(*c.Default, "nextval(")
+		c.Generated = generated == "ALWAYS"
+
+		// move postgres sizes of materialized view columns to the length, precision and scale
+		c.stripSize()
+
+		// unrecognized types are left without a definition for type overrides
+		c.Definition = postgresTypeMap[c.DatabaseType]
+		t.Columns = append(t.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("No rows returned from the information schema for database %s, TABLE_SCHEMA %s and TABLE_NAME %s", database, schema, name)
+	}
+	if t.Indexes, err = queryIndexes(ctx, db, postgresIndexQuery, schema, name); err != nil {
+		return nil, err
+	}
+	if t.ForeignKeys, err = queryForeignKeys(ctx, db, postgresForeignKeyQuery, schema, name); err != nil {
+		return nil, err
+	}
+	t.markKeys()
+	return &t, nil
+}
+
+// ListTables returns the names of the tables in the schemas of the database, names are schema
+// qualified when tables are listed from more than one schema
+func (p PostgreSQL) ListTables(ctx context.Context, database string) ([]string, error) {
+	return p.list(ctx, database, postgresTableQuery)
+}
+
+// ListViews returns the names of the views and materialized views in the schemas of the database,
+// names are schema qualified when views are listed from more than one schema
+func (p PostgreSQL) ListViews(ctx context.Context, database string) ([]string, error) {
+	return p.list(ctx, database, postgresViewQuery)
+}
+
+func (p PostgreSQL) list(ctx context.Context, database, query string) ([]string, error) {
+	db, err := p.open(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	schemas, err := p.schemas(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, schema := range schemas {
+		rows, err := db.QueryContext(ctx, query, schema)
+		if err != nil {
+			return nil, err
+		}
+		tables, err := scanNames(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tables {
+			if len(schemas) > 1 {
+				table = schema + "." + table
+			}
+			names = append(names, table)
+		}
+	}
+	return names, nil
+}
+
+// schemas returns the configured schemas, or the schemas of the search_path of the connection
+func (p PostgreSQL) schemas(ctx context.Context, db *sql.DB) ([]string, error) {
+	if len(p.Schemas) > 0 {
+		return p.Schemas, nil
+	}
+	rows, err := db.QueryContext(ctx, postgresSearchPathQuery)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := scanNames(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("the search_path has no existing schemas, set the schemas to generate from")
+	}
+	return schemas, nil
+}
+
+// resolve splits a schema qualified table name, or returns the first of the schemas holding the table
+func (p PostgreSQL) resolve(ctx context.Context, db *sql.DB, table string) (string, string, error) {
+	if i := strings.Index(table, "."); i >= 0 {
+		return table[:i], table[i+1:], nil
+	}
+	schemas, err := p.schemas(ctx, db)
+	if err != nil {
+		return "", "", err
+	}
+	var schema string
+	err = db.QueryRowContext(ctx, postgresResolveQuery, table, pq.Array(schemas)).Scan(&schema)
+	if err == sql.ErrNoRows {
+		return "", "", fmt.Errorf("table %s not found in the schemas %s", table, strings.Join(schemas, ", "))
+	}
+	return schema, table, err
+}
+
+// open connects to the database, the password is read from the password file when none is set
+func (p PostgreSQL) open(ctx context.Context, database string) (*sql.DB, error) {
+	conn, err := p.withPgpass(database)
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := conn.postgresDSN(database)
+	if err != nil {
+		return nil, err
+	}
+	return open(ctx, "postgres", dsn)
+}
+
+var postgresTypeMap = map[string]ColumnDefinition{
+	// integer fields
+	"bigint":      ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"bigserial":   ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"integer":     ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int":         ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int4":        ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"smallint":    ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int2":        ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"smallserial": ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"serial2":     ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"serial":      ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"serial4":     ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	// string fields
+	"char":              ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"character":         ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"character varying": ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"varchar":           ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"cidr":              ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"inet":              ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"json":              ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"macaddr":           ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"text":              ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"xml":               ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	// time fields
+	"date":                        ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"time":                        ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"time without time zone":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"time with time zone":         ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timetz":                      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamp":                   ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamp without time zone": ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamp with time zone":    ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamptz":                 ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"interval":                    ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	// float fields
+	"double precision": ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float8":           ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"money":            ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"numeric":          ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"decimal":          ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"real":             ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	// binary fields
+	"bit":         ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"bit varying": ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"bytea":       ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"jsonb":       ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	// bool
+	"boolean": ColumnDefinition{GoType: "bool", GureguType: "null.Bool", SQLType: "null.Bool"},
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// preciseIntegers maps the integer and floating point types of each dialect to the go type of
+// the same size, unsigned columns use the unsigned go type
+var preciseIntegers = map[string]map[string]string{
+	"mariadb": mySQLIntegers,
+	"mysql":   mySQLIntegers,
+	"postgresql": map[string]string{
+		"smallint": "int16", "int2": "int16", "smallserial": "int16", "serial2": "int16",
+		"integer": "int32", "int": "int32", "int4": "int32", "serial": "int32", "serial4": "int32",
+		"bigint": "int64", "int8": "int64", "bigserial": "int64", "serial8": "int64",
+		"real": "float32",
+	},
+	"mssql": map[string]string{
+		"tinyint": "uint8", "smallint": "int16", "int": "int32", "bigint": "int64", "real": "float32",
+	},
+	// vertica stores every integer as 64 bits
+	"vertica": map[string]string{
+		"tinyint": "int64", "smallint": "int64", "int": "int64", "integer": "int64", "bigint": "int64", "int8": "int64",
+	},
+}
+
+var mySQLIntegers = map[string]string{
+	"tinyint": "int8", "smallint": "int16", "mediumint": "int32", "int": "int32", "integer": "int32", "bigint": "int64",
+	"float": "float32",
+}
+
+// preciseDecimals are the exact numeric types of each dialect
+var preciseDecimals = map[string]map[string]bool{
+	"mariadb":    map[string]bool{"decimal": true, "numeric": true, "dec": true, "fixed": true},
+	"mysql":      map[string]bool{"decimal": true, "numeric": true, "dec": true, "fixed": true},
+	"postgresql": map[string]bool{"decimal": true, "numeric": true},
+	"mssql":      map[string]bool{"decimal": true, "numeric": true, "money": true, "smallmoney": true},
+	"vertica":    map[string]bool{"decimal": true, "numeric": true, "number": true, "money": true},
+	"sqlite":     map[string]bool{"decimal": true, "numeric": true},
+}
+
+// preciseDefinitions are the definitions of the sized go types. There is no unsigned 64 bit nullable
+// type and values above the largest int64 overflow sql.NullInt64, nullable unsigned 64 bit integers
+// are read as the decimal string of the value.
+var preciseDefinitions = map[string]ColumnDefinition{
+	"int8":    ColumnDefinition{GoType: "int8", GureguType: "null.Int", SQLType: "sql.NullInt16"},
+	"uint8":   ColumnDefinition{GoType: "uint8", GureguType: "null.Int", SQLType: "sql.NullByte"},
+	"int16":   ColumnDefinition{GoType: "int16", GureguType: "null.Int", SQLType: "sql.NullInt16"},
+	"uint16":  ColumnDefinition{GoType: "uint16", GureguType: "null.Int", SQLType: "sql.NullInt32"},
+	"int32":   ColumnDefinition{GoType: "int32", GureguType: "null.Int", SQLType: "sql.NullInt32"},
+	"uint32":  ColumnDefinition{GoType: "uint32", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int64":   ColumnDefinition{GoType: "int64", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"uint64":  ColumnDefinition{GoType: "uint64", GureguType: "null.String", SQLType: "sql.NullString"},
+	"float32": ColumnDefinition{GoType: "float32", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+}
+
+// decimalDefinitions are the exact go types decimal columns can be mapped to
+var decimalDefinitions = map[string]ColumnDefinition{
+	"shopspring": ColumnDefinition{GoType: "decimal.Decimal", GureguType: "decimal.NullDecimal", SQLType: "decimal.NullDecimal", Import: "github.com/shopspring/decimal"},
+	"apd":        ColumnDefinition{GoType: "apd.Decimal", GureguType: "apd.NullDecimal", SQLType: "apd.NullDecimal", Import: "github.com/cockroachdb/apd/v3"},
+	"string":     ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+}
+
+// Precise maps the integer columns of the table to the go type of their size and the decimal columns
+// to the exact decimal type, shopspring, apd or string
+func (t *Table) Precise(decimal string) error {
+	decimalDefinition, ok := decimalDefinitions[decimal]
+	if !ok {
+		var names []string
+		for name := range decimalDefinitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("Unrecognized decimal type: %s, expected one of %s", decimal, strings.Join(names, ", "))
+	}
+	for i := range t.Columns {
+		c := &t.Columns[i]
+		goType := preciseIntegers[t.Dialect][c.DatabaseType]
+		switch {
+		case preciseDecimals[t.Dialect][c.DatabaseType]:
+			c.Definition = decimalDefinition
+		case goType != "":
+			if c.Unsigned && strings.HasPrefix(goType, "int") {
+				goType = "u" + goType
+			}
+			c.Definition = preciseDefinitions[goType]
+		case t.Dialect == "sqlite" && c.Definition.GoType == "int":
+			// sqlite stores every integer as 64 bits
+			c.Definition = preciseDefinitions["int64"]
+		}
+	}
+	return nil
+}
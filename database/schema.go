@@ -0,0 +1,918 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Schema is an in memory schema model built by applying DDL statements of a dialect.
+// It builds tables without connecting to a database.
+type Schema struct {
+	Dialect string
+	tables  map[string]*Table // keyed by the lower case qualifier and table name
+}
+
+// NewSchema returns an empty schema for the dialect
+func NewSchema(dialect string) (*Schema, error) {
+	if err := validDialect(dialect); err != nil {
+		return nil, err
+	}
+	return &Schema{Dialect: dialect, tables: map[string]*Table{}}, nil
+}
+
+// ApplyFile applies every statement of the sql file to the schema
+func (s *Schema) ApplyFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Apply(string(b)); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	return nil
+}
+
+// Apply applies the statements of the sql text to the schema. Only statements creating, altering,
+// renaming and dropping tables change the schema, every other statement is ignored.
+func (s *Schema) Apply(sql string) error {
+	tokens, err := tokenize(s.Dialect, sql)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range statements(tokens) {
+		p := &parser{tokens: stmt}
+		if err := s.apply(p); err != nil {
+			return fmt.Errorf("line %d: %s", p.line(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) apply(p *parser) error {
+	switch {
+	case p.keyword("create"):
+		return s.create(p)
+	case p.keyword("drop", "table"):
+		return s.drop(p)
+	case p.keyword("drop", "index"):
+		return s.dropIndex(p)
+	case p.keyword("alter", "table"):
+		return s.alter(p)
+	case p.keyword("rename", "table"):
+		return s.rename(p)
+	}
+	return nil
+}
+
+// create applies a create table statement, ex: CREATE TABLE IF NOT EXISTS test.users (id int NOT NULL)
+func (s *Schema) create(p *parser) error {
+	p.keyword("or", "replace")
+	for _, k := range []string{"global", "local", "temporary", "temp", "unlogged"} {
+		p.keyword(k)
+	}
+	unique := p.keyword("unique")
+	for _, k := range []string{"clustered", "nonclustered", "fulltext", "spatial"} {
+		p.keyword(k)
+	}
+	if p.keyword("index") {
+		return s.createIndex(p, unique)
+	}
+	if unique || !p.keyword("table") {
+		return nil
+	}
+	ifNotExists := p.keyword("if", "not", "exists")
+	qualifier, name, err := p.qualifiedName(s.fold())
+	if err != nil {
+		return err
+	}
+	if ifNotExists && s.lookup(qualifier, name) != nil {
+		return nil
+	}
+
+	t := &Table{Name: name}
+	switch {
+	case p.keyword("like"):
+		// mysql copies the definition of an existing table including its indexes
+		if err := s.copyColumns(p, t, true); err != nil {
+			return err
+		}
+	case p.peek().isSymbol("("):
+		elements, err := p.group()
+		if err != nil {
+			return err
+		}
+		if err := s.elements(t, elements); err != nil {
+			return err
+		}
+	default:
+		// create table ... as select can not be resolved without a database
+		return nil
+	}
+	// the qualifier is kept in the schema of the table whatever it means for the dialect
+	t.Schema = qualifier
+	s.tables[s.key(qualifier, name)] = t
+	return nil
+}
+
+// elements adds the columns and constraints of a create table column list
+func (s *Schema) elements(t *Table, elements []token) error {
+	var indexes []Index
+	var keys []ForeignKey
+	for _, element := range splitList(elements) {
+		if len(element) == 0 {
+			continue
+		}
+		ep := &parser{tokens: element}
+		switch {
+		case ep.keyword("like"):
+			// postgres only copies the indexes when asked to, ex: LIKE users INCLUDING ALL
+			including := false
+			for i := range element {
+				if element[i].is("including") && i+1 < len(element) && (element[i+1].is("indexes") || element[i+1].is("all")) {
+					including = true
+				}
+			}
+			if err := s.copyColumns(ep, t, including); err != nil {
+				return err
+			}
+			continue
+		case isTableConstraint(ep):
+			if i, ok := constraintIndex(ep, s.fold()); ok {
+				indexes = append(indexes, i)
+			} else if key, ok := constraintForeignKey(&parser{tokens: element}, s.fold()); ok {
+				keys = append(keys, key)
+			}
+			continue
+		}
+		c, err := parseColumn(s.Dialect, element)
+		if err != nil {
+			return err
+		}
+		t.Columns = append(t.Columns, c.Column)
+		indexes = append(indexes, c.indexes()...)
+		if c.references != nil {
+			keys = append(keys, *c.references)
+		}
+	}
+	// indexes and foreign keys are added once every column is known
+	for _, i := range indexes {
+		s.addIndex(t, i)
+	}
+	for _, key := range keys {
+		addForeignKey(t, key)
+	}
+	return nil
+}
+
+// copyColumns copies the columns of the table named by the parser, and its indexes when indexes is set
+func (s *Schema) copyColumns(p *parser, t *Table, indexes bool) error {
+	qualifier, name, err := p.qualifiedName(s.fold())
+	if err != nil {
+		return err
+	}
+	source := s.lookup(qualifier, name)
+	if source == nil {
+		return fmt.Errorf("table %s does not exist", name)
+	}
+	t.Columns = append(t.Columns, source.Columns...)
+	if indexes {
+		for _, i := range source.Indexes {
+			i.Name = ""
+			s.addIndex(t, i)
+		}
+	}
+	return nil
+}
+
+// createIndex applies a create index statement, ex: CREATE UNIQUE INDEX IF NOT EXISTS users_email ON users (email)
+func (s *Schema) createIndex(p *parser, unique bool) error {
+	fold := s.fold()
+	p.keyword("concurrently")
+	p.keyword("if", "not", "exists")
+	var name string
+	if !p.peek().is("on") {
+		var err error
+		if _, name, err = p.qualifiedName(fold); err != nil {
+			return err
+		}
+	}
+	if !p.keyword("on") {
+		return fmt.Errorf("expected ON in create index %s", name)
+	}
+	p.keyword("only")
+	qualifier, table, err := p.qualifiedName(fold)
+	if err != nil {
+		return err
+	}
+	t := s.lookup(qualifier, table)
+	if t == nil {
+		return fmt.Errorf("table %s does not exist", table)
+	}
+	if p.keyword("using") {
+		p.next()
+	}
+	columns, ok := indexColumns(p, fold)
+	if !ok {
+		// expression indexes do not index columns
+		return nil
+	}
+	s.addIndex(t, Index{Name: name, Columns: columns, Unique: unique})
+	return nil
+}
+
+// dropIndex applies a drop index statement, ex: DROP INDEX IF EXISTS users_email or DROP INDEX users_email ON users
+func (s *Schema) dropIndex(p *parser) error {
+	fold := s.fold()
+	p.keyword("concurrently")
+	p.keyword("if", "exists")
+	var names []string
+	for !p.done() {
+		// sql server names the index by its table, ex: users.users_email
+		_, name, err := p.qualifiedName(fold)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		if !p.peek().isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	var tables []*Table
+	if p.keyword("on") {
+		qualifier, table, err := p.qualifiedName(fold)
+		if err != nil {
+			return err
+		}
+		if t := s.lookup(qualifier, table); t != nil {
+			tables = append(tables, t)
+		}
+	} else {
+		for _, t := range s.tables {
+			tables = append(tables, t)
+		}
+	}
+	for _, t := range tables {
+		for _, name := range names {
+			removeIndex(t, name)
+		}
+	}
+	return nil
+}
+
+// drop applies a drop table statement, ex: DROP TABLE IF EXISTS users, admins CASCADE
+func (s *Schema) drop(p *parser) error {
+	ifExists := p.keyword("if", "exists")
+	for !p.done() {
+		qualifier, name, err := p.qualifiedName(s.fold())
+		if err != nil {
+			return err
+		}
+		if s.lookup(qualifier, name) == nil {
+			if !ifExists {
+				return fmt.Errorf("table %s does not exist", name)
+			}
+		} else {
+			delete(s.tables, s.lookupKey(qualifier, name))
+		}
+		if !p.peek().isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return nil
+}
+
+// alter applies the actions of an alter table statement, ex: ALTER TABLE users ADD COLUMN email text, DROP COLUMN name
+func (s *Schema) alter(p *parser) error {
+	ifExists := p.keyword("if", "exists")
+	p.keyword("only")
+	qualifier, name, err := p.qualifiedName(s.fold())
+	if err != nil {
+		return err
+	}
+	t := s.lookup(qualifier, name)
+	if t == nil {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("table %s does not exist", name)
+	}
+	for _, action := range splitList(p.tokens[p.pos:]) {
+		ap := &parser{tokens: action}
+		if err := s.alterAction(ap, qualifier, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterAction applies a single action of an alter table statement to the table
+func (s *Schema) alterAction(p *parser, qualifier string, t *Table) error {
+	fold := s.fold()
+	switch {
+	case p.keyword("add"):
+		if isTableConstraint(p) {
+			start := p.pos
+			if i, ok := constraintIndex(p, fold); ok {
+				s.addIndex(t, i)
+			} else if key, ok := constraintForeignKey(&parser{tokens: p.tokens[start:]}, fold); ok {
+				addForeignKey(t, key)
+			}
+			return nil
+		}
+		p.keyword("column")
+		p.keyword("if", "not", "exists")
+		// mysql adds a list of columns in parentheses
+		if p.peek().isSymbol("(") {
+			elements, err := p.group()
+			if err != nil {
+				return err
+			}
+			return s.elements(t, elements)
+		}
+		element, position := columnPosition(p.tokens[p.pos:])
+		c, err := parseColumn(s.Dialect, element)
+		if err != nil {
+			return err
+		}
+		if findColumn(t, c.Name) != nil {
+			return nil
+		}
+		insertColumn(t, c.Column, position)
+		for _, i := range c.indexes() {
+			s.addIndex(t, i)
+		}
+		if c.references != nil {
+			addForeignKey(t, *c.references)
+		}
+	case p.keyword("drop"):
+		switch {
+		case p.keyword("primary", "key"):
+			if i := primaryIndex(t); i != nil {
+				removeIndex(t, i.Name)
+			}
+			return nil
+		case p.keyword("constraint"), p.keyword("index"), p.keyword("key"), p.keyword("foreign", "key"):
+			p.keyword("if", "exists")
+			name, err := p.identifier(fold)
+			if err != nil {
+				return err
+			}
+			removeIndex(t, name)
+			removeForeignKey(t, name)
+			return nil
+		case p.keyword("check"), p.keyword("period", "for"):
+			// dropping checks and periods leaves the columns unchanged
+			return nil
+		}
+		p.keyword("column")
+		p.keyword("if", "exists")
+		name, err := p.identifier(fold)
+		if err != nil {
+			return err
+		}
+		s.removeIndexColumn(t, name)
+		removeForeignKeyColumn(t, name)
+		removeColumn(t, name)
+	case p.keyword("rename"):
+		switch {
+		case p.keyword("to"), p.keyword("as"):
+			newQualifier, newName, err := p.qualifiedName(fold)
+			if err != nil {
+				return err
+			}
+			if newQualifier == "" {
+				newQualifier = qualifier
+			}
+			return s.renameTable(qualifier, t.Name, newQualifier, newName)
+		case p.keyword("index"), p.keyword("key"):
+			old, err := p.identifier(fold)
+			if err != nil {
+				return err
+			}
+			if !p.keyword("to") {
+				return fmt.Errorf("expected TO in rename of index %s", old)
+			}
+			name, err := p.identifier(fold)
+			if err != nil {
+				return err
+			}
+			for n := range t.Indexes {
+				if strings.EqualFold(t.Indexes[n].Name, old) {
+					t.Indexes[n].Name = name
+				}
+			}
+			return nil
+		case p.peek().is("constraint"):
+			return nil
+		}
+		p.keyword("column")
+		old, err := p.identifier(fold)
+		if err != nil {
+			return err
+		}
+		if !p.keyword("to") {
+			return fmt.Errorf("expected TO in rename of column %s", old)
+		}
+		name, err := p.identifier(fold)
+		if err != nil {
+			return err
+		}
+		if c := findColumn(t, old); c != nil {
+			s.renameColumn(t, c.Name, name)
+			c.Name = name
+		}
+	case p.keyword("modify"):
+		// mysql redefines a column
+		p.keyword("column")
+		element, position := columnPosition(p.tokens[p.pos:])
+		c, err := parseColumn(s.Dialect, element)
+		if err != nil {
+			return err
+		}
+		return replaceColumn(t, c.Name, c.Column, position)
+	case p.keyword("change"):
+		// mysql redefines and renames a column
+		p.keyword("column")
+		old, err := p.identifier(fold)
+		if err != nil {
+			return err
+		}
+		element, position := columnPosition(p.tokens[p.pos:])
+		c, err := parseColumn(s.Dialect, element)
+		if err != nil {
+			return err
+		}
+		if existing := findColumn(t, old); existing != nil {
+			s.renameColumn(t, existing.Name, c.Name)
+		}
+		return replaceColumn(t, old, c.Column, position)
+	case p.keyword("alter"):
+		p.keyword("column")
+		nameToken := p.peek()
+		name, err := p.identifier(fold)
+		if err != nil {
+			return err
+		}
+		c := findColumn(t, name)
+		if c == nil {
+			return fmt.Errorf("column %s does not exist in table %s", name, t.Name)
+		}
+		switch {
+		case p.keyword("set", "not", "null"):
+			c.DatabaseNullable = "NO"
+		case p.keyword("drop", "not", "null"):
+			c.DatabaseNullable = "YES"
+		case p.keyword("set", "default"):
+			value := defaultExpression(p)
+			c.Default = &value
+		case p.keyword("drop", "default"):
+			c.Default = nil
+		case p.keyword("add", "generated"):
+			// postgresql identity columns are not null, ex: ADD GENERATED ALWAYS AS IDENTITY
+			for !p.done() {
+				if p.next().is("identity") {
+					c.DatabaseNullable = "NO"
+					c.AutoIncrement = true
+				}
+			}
+		case p.keyword("drop", "identity"):
+			c.AutoIncrement = false
+		case p.keyword("type"), p.keyword("set", "data", "type"):
+			altered, err := parseColumn(s.Dialect, append([]token{nameToken}, p.tokens[p.pos:]...))
+			if err != nil {
+				return err
+			}
+			c.DatabaseType = altered.DatabaseType
+			c.Length, c.Precision, c.Scale = altered.Length, altered.Precision, altered.Scale
+		case s.Dialect == "mssql":
+			// sql server redefines the type and nullability, ex: ALTER COLUMN name varchar(20) NOT NULL
+			altered, err := parseColumn(s.Dialect, append([]token{nameToken}, p.tokens[p.pos:]...))
+			if err != nil {
+				return err
+			}
+			c.DatabaseType = altered.DatabaseType
+			c.Length, c.Precision, c.Scale = altered.Length, altered.Precision, altered.Scale
+			c.DatabaseNullable = altered.DatabaseNullable
+		}
+	}
+	return nil
+}
+
+// rename applies a mysql rename table statement, ex: RENAME TABLE users TO accounts, a TO b
+func (s *Schema) rename(p *parser) error {
+	for _, item := range splitList(p.tokens[p.pos:]) {
+		ip := &parser{tokens: item}
+		qualifier, name, err := ip.qualifiedName(s.fold())
+		if err != nil {
+			return err
+		}
+		if !ip.keyword("to") {
+			return fmt.Errorf("expected TO in rename of table %s", name)
+		}
+		newQualifier, newName, err := ip.qualifiedName(s.fold())
+		if err != nil {
+			return err
+		}
+		if err := s.renameTable(qualifier, name, newQualifier, newName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) renameTable(qualifier, name, newQualifier, newName string) error {
+	t := s.lookup(qualifier, name)
+	if t == nil {
+		return fmt.Errorf("table %s does not exist", name)
+	}
+	delete(s.tables, s.lookupKey(qualifier, name))
+	// foreign keys follow the renamed table
+	follow := func(k *ForeignKey) {
+		if strings.EqualFold(k.ReferencedTable, t.Name) && (k.ReferencedSchema == "" || strings.EqualFold(k.ReferencedSchema, t.Schema)) {
+			k.ReferencedTable = newName
+			if k.ReferencedSchema != "" {
+				k.ReferencedSchema = newQualifier
+			}
+		}
+	}
+	for _, other := range s.tables {
+		for i := range other.ForeignKeys {
+			follow(&other.ForeignKeys[i])
+		}
+	}
+	for i := range t.ForeignKeys {
+		follow(&t.ForeignKeys[i])
+	}
+	t.Name, t.Schema = newName, newQualifier
+	s.tables[s.key(newQualifier, newName)] = t
+	return nil
+}
+
+// Build returns the table of the schema, the database name matches the qualifier of the statement
+// that created it. Unqualified tables and tables that have a unique name match any database. Without
+// a database the table name can be qualified, as listed by ListTables.
+func (s *Schema) Build(ctx context.Context, database, table string) (*Table, error) {
+	if i := strings.LastIndex(table, "."); database == "" && i > 0 {
+		database, table = table[:i], table[i+1:]
+	}
+	source := s.lookup(database, table)
+	if source == nil {
+		return nil, fmt.Errorf("No table %s found in the schema for database %s", table, database)
+	}
+
+	t := Table{Name: source.Name, Type: BaseTable, Dialect: s.Dialect}
+	if schemaQualifiers[s.Dialect] {
+		t.Schema = source.Schema
+	}
+	for _, c := range source.Columns {
+		// unrecognized types are left without a definition for type overrides
+		c.Definition, _ = definition(s.Dialect, c.DatabaseType)
+		t.Columns = append(t.Columns, c)
+	}
+	for _, i := range source.Indexes {
+		i.Columns = append([]string(nil), i.Columns...)
+		t.Indexes = append(t.Indexes, i)
+	}
+	for _, k := range source.ForeignKeys {
+		// the referenced schema is only kept when it is a schema other than the schema of the table
+		if !schemaQualifiers[s.Dialect] || strings.EqualFold(k.ReferencedSchema, source.Schema) {
+			k.ReferencedSchema = ""
+		}
+		k.Columns = append([]string(nil), k.Columns...)
+		k.ReferencedColumns = append([]string(nil), k.ReferencedColumns...)
+		t.ForeignKeys = append(t.ForeignKeys, k)
+	}
+	t.markKeys()
+	return &t, nil
+}
+
+// ListTables returns the names of the tables created with the database as qualifier or without
+// qualifier. Without a database every table is listed, tables created with a qualifier are listed
+// with their qualified name, ex: public.users.
+func (s *Schema) ListTables(ctx context.Context, database string) ([]string, error) {
+	var names []string
+	for k, t := range s.tables {
+		switch {
+		case database == "" && t.Schema != "":
+			names = append(names, t.Schema+"."+t.Name)
+		case database == "" || strings.HasPrefix(k, ".") || strings.HasPrefix(k, s.key(database, "")):
+			names = append(names, t.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// schemaQualifiers are the dialects whose table qualifier is a schema of the database, the tables
+// built from them have the schema as the tables of the database server do. The qualifier of the
+// other dialects is the database.
+var schemaQualifiers = map[string]bool{"postgresql": true, "mssql": true}
+
+// ListViews returns no views, the columns of a view can not be resolved from its DDL
+func (s *Schema) ListViews(ctx context.Context, database string) ([]string, error) {
+	return nil, nil
+}
+
+// lookup returns the table with the qualifier and name or nil
+func (s *Schema) lookup(qualifier, name string) *Table {
+	return s.tables[s.lookupKey(qualifier, name)]
+}
+
+// lookupKey resolves the key of a table. Tables are matched on their qualifier first, then
+// without qualifier, then on the postgresql folded name such as users for Users, and finally on
+// their name alone when it is unique.
+func (s *Schema) lookupKey(qualifier, name string) string {
+	names := []string{name}
+	if s.fold() && strings.ToLower(name) != name {
+		names = append(names, strings.ToLower(name))
+	}
+	for _, name := range names {
+		if _, ok := s.tables[s.key(qualifier, name)]; ok {
+			return s.key(qualifier, name)
+		}
+		if _, ok := s.tables[s.key("", name)]; ok {
+			return s.key("", name)
+		}
+	}
+	var found []string
+	for k, t := range s.tables {
+		if strings.EqualFold(t.Name, name) {
+			found = append(found, k)
+		}
+	}
+	if len(found) == 1 {
+		return found[0]
+	}
+	return s.key(qualifier, name)
+}
+
+// fold reports whether unquoted identifiers are folded to lower case
+func (s *Schema) fold() bool {
+	return s.Dialect == "postgresql"
+}
+
+// key returns the key of the table with the qualifier and name. Postgresql identifiers are folded
+// when they are parsed unless they are quoted, so the key keeps their case and "Users" and users are
+// different tables, the identifiers of the other dialects are compared without case.
+func (s *Schema) key(qualifier, name string) string {
+	if s.fold() {
+		return qualifier + "." + name
+	}
+	return strings.ToLower(qualifier + "." + name)
+}
+
+func findColumn(t *Table, name string) *Column {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+func removeColumn(t *Table, name string) {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+			return
+		}
+	}
+}
+
+// insertColumn adds the column at the mysql position, FIRST or AFTER column, or at the end
+func insertColumn(t *Table, c Column, position []token) {
+	i := len(t.Columns)
+	switch {
+	case len(position) > 0 && position[0].is("first"):
+		i = 0
+	case len(position) > 1 && position[0].is("after"):
+		for j := range t.Columns {
+			if strings.EqualFold(t.Columns[j].Name, position[1].text) {
+				i = j + 1
+			}
+		}
+	}
+	t.Columns = append(t.Columns, Column{})
+	copy(t.Columns[i+1:], t.Columns[i:])
+	t.Columns[i] = c
+}
+
+// replaceColumn replaces the named column with its new definition
+func replaceColumn(t *Table, name string, c Column, position []token) error {
+	if findColumn(t, name) == nil {
+		return fmt.Errorf("column %s does not exist in table %s", name, t.Name)
+	}
+	if len(position) == 0 {
+		*findColumn(t, name) = c
+		return nil
+	}
+	removeColumn(t, name)
+	insertColumn(t, c, position)
+	return nil
+}
+
+// columnPosition splits a trailing mysql FIRST or AFTER column position from a column definition
+func columnPosition(tokens []token) ([]token, []token) {
+	for i, t := range tokens {
+		if i > 0 && (t.is("first") || t.is("after")) {
+			return tokens[:i], tokens[i:]
+		}
+	}
+	return tokens, nil
+}
+
+// isTableConstraint reports whether the create table element is a constraint instead of a column.
+// Words such as key, index or period also name columns, a column name is followed by its type while
+// a constraint keyword is followed by the rest of the constraint, ex: KEY name (...), PERIOD FOR.
+func isTableConstraint(p *parser) bool {
+	tokens := p.tokens[p.pos:]
+	if len(tokens) < 2 {
+		return false
+	}
+	first, second := tokens[0], tokens[1]
+	switch {
+	case first.is("constraint"):
+		return len(tokens) > 2 && isConstraintKeyword(tokens[2])
+	case first.is("primary"), first.is("foreign"):
+		return second.is("key")
+	case first.is("check"):
+		return second.isSymbol("(") || second.is("not")
+	case first.is("exclude"):
+		return second.isSymbol("(") || second.is("using")
+	case first.is("period"):
+		return second.is("for")
+	case first.is("unique"), first.is("key"), first.is("index"), first.is("fulltext"), first.is("spatial"):
+		return second.isSymbol("(") || second.is("key") || second.is("index") || second.is("using") ||
+			second.is("nulls") || second.is("clustered") || second.is("nonclustered") || isIndexName(tokens[1:])
+	}
+	return false
+}
+
+// isConstraintKeyword reports whether the token starts the constraint following CONSTRAINT name
+func isConstraintKeyword(t token) bool {
+	for _, k := range tableConstraintKeywords {
+		if t.is(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIndexName reports whether the tokens are an index name followed by its column list or USING,
+// rather than a column type such as varchar(10) whose parentheses hold numbers or strings
+func isIndexName(tokens []token) bool {
+	if len(tokens) < 3 || tokens[0].kind != wordToken && tokens[0].kind != identToken {
+		return false
+	}
+	if tokens[1].is("using") {
+		return true
+	}
+	return tokens[1].isSymbol("(") && (tokens[2].kind == wordToken || tokens[2].kind == identToken)
+}
+
+// addIndex adds the index to the table, replacing an existing index of the same name or primary key.
+// Unnamed indexes are named the way the dialect names them and primary key columns can never be null.
+func (s *Schema) addIndex(t *Table, i Index) {
+	for n, name := range i.Columns {
+		if c := findColumn(t, name); c != nil {
+			i.Columns[n] = c.Name
+			// only integer primary keys are not null in sqlite
+			if i.Primary && (s.Dialect != "sqlite" || c.DatabaseType == "integer") {
+				c.DatabaseNullable = "NO"
+			}
+		}
+	}
+	if i.Name == "" {
+		i.Name = indexName(s.Dialect, t.Name, i)
+	}
+	for n := range t.Indexes {
+		if strings.EqualFold(t.Indexes[n].Name, i.Name) || (i.Primary && t.Indexes[n].Primary) {
+			t.Indexes[n] = i
+			return
+		}
+	}
+	t.Indexes = append(t.Indexes, i)
+}
+
+// removeIndexColumn removes a dropped column from the indexes of the table. Postgres drops the
+// indexes of the column while mysql only removes it from the index.
+func (s *Schema) removeIndexColumn(t *Table, column string) {
+	var indexes []Index
+	for _, i := range t.Indexes {
+		var columns []string
+		for _, name := range i.Columns {
+			if !strings.EqualFold(name, column) {
+				columns = append(columns, name)
+			}
+		}
+		if len(columns) == len(i.Columns) || (len(columns) > 0 && (s.Dialect == "mariadb" || s.Dialect == "mysql")) {
+			i.Columns = columns
+			indexes = append(indexes, i)
+		}
+	}
+	t.Indexes = indexes
+}
+
+// indexName returns the name the dialect gives an unnamed index
+func indexName(dialect, table string, i Index) string {
+	if dialect == "mariadb" || dialect == "mysql" {
+		if i.Primary {
+			return "PRIMARY"
+		}
+		return i.Columns[0]
+	}
+	switch {
+	case i.Primary:
+		return table + "_pkey"
+	case i.Unique:
+		return table + "_" + strings.Join(i.Columns, "_") + "_key"
+	}
+	return table + "_" + strings.Join(i.Columns, "_") + "_idx"
+}
+
+func primaryIndex(t *Table) *Index {
+	for n := range t.Indexes {
+		if t.Indexes[n].Primary {
+			return &t.Indexes[n]
+		}
+	}
+	return nil
+}
+
+func removeIndex(t *Table, name string) {
+	for n := range t.Indexes {
+		if strings.EqualFold(t.Indexes[n].Name, name) {
+			t.Indexes = append(t.Indexes[:n], t.Indexes[n+1:]...)
+			return
+		}
+	}
+}
+
+// renameColumn renames the column in the indexes and foreign keys of the table and in the foreign
+// keys referencing it
+func (s *Schema) renameColumn(t *Table, old, name string) {
+	for _, i := range t.Indexes {
+		renameIn(i.Columns, old, name)
+	}
+	for _, k := range t.ForeignKeys {
+		renameIn(k.Columns, old, name)
+	}
+	for _, other := range s.tables {
+		for _, k := range other.ForeignKeys {
+			if strings.EqualFold(k.ReferencedTable, t.Name) {
+				renameIn(k.ReferencedColumns, old, name)
+			}
+		}
+	}
+}
+
+func renameIn(names []string, old, name string) {
+	for n := range names {
+		if strings.EqualFold(names[n], old) {
+			names[n] = name
+		}
+	}
+}
+
+// addForeignKey adds the foreign key to the table, unnamed foreign keys are named after the table and their columns
+func addForeignKey(t *Table, k ForeignKey) {
+	for n, name := range k.Columns {
+		if c := findColumn(t, name); c != nil {
+			k.Columns[n] = c.Name
+		}
+	}
+	if k.Name == "" {
+		k.Name = t.Name + "_" + strings.Join(k.Columns, "_") + "_fkey"
+	}
+	removeForeignKey(t, k.Name)
+	t.ForeignKeys = append(t.ForeignKeys, k)
+}
+
+func removeForeignKey(t *Table, name string) {
+	for n := range t.ForeignKeys {
+		if strings.EqualFold(t.ForeignKeys[n].Name, name) {
+			t.ForeignKeys = append(t.ForeignKeys[:n], t.ForeignKeys[n+1:]...)
+			return
+		}
+	}
+}
+
+// removeForeignKeyColumn drops the foreign keys of a dropped column
+func removeForeignKeyColumn(t *Table, column string) {
+	var keys []ForeignKey
+	for _, k := range t.ForeignKeys {
+		dropped := false
+		for _, name := range k.Columns {
+			dropped = dropped || strings.EqualFold(name, column)
+		}
+		if !dropped {
+			keys = append(keys, k)
+		}
+	}
+	t.ForeignKeys = keys
+}
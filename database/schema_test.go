@@ -0,0 +1,372 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// tableString describes the columns, indexes and foreign keys of the table one per line
+func tableString(t *Table) string {
+	var lines []string
+	for _, c := range t.Columns {
+		lines = append(lines, columnString(c))
+	}
+	for _, i := range t.Indexes {
+		kind := "index"
+		switch {
+		case i.Primary:
+			kind = "primary"
+		case i.Unique:
+			kind = "unique"
+		}
+		lines = append(lines, kind+" "+i.Name+" ("+strings.Join(i.Columns, ", ")+")")
+	}
+	for _, k := range t.ForeignKeys {
+		referenced := k.ReferencedTable
+		if k.ReferencedSchema != "" {
+			referenced = k.ReferencedSchema + "." + referenced
+		}
+		lines = append(lines, "foreign "+k.Name+" ("+strings.Join(k.Columns, ", ")+") "+referenced+" ("+strings.Join(k.ReferencedColumns, ", ")+")")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildTable applies the sql to a new schema of the dialect and builds the table
+func buildTable(dialect, sql, table string) (*Table, error) {
+	s, err := NewSchema(dialect)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Apply(sql); err != nil {
+		return nil, err
+	}
+	return s.Build(context.Background(), "", table)
+}
+
+func TestSchemaCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		table   string
+		want    string
+	}{
+		{
+			name:    "inline constraints",
+			dialect: "postgresql",
+			sql: `CREATE TABLE users (id serial PRIMARY KEY, email text UNIQUE NOT NULL);
+				CREATE TABLE orders (id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, user_id int REFERENCES users, total numeric(10,2) DEFAULT 0);`,
+			table: "orders",
+			want: `id bigint not null auto
+user_id int
+total numeric(10,2) default 0
+primary orders_pkey (id)
+foreign orders_user_id_fkey (user_id) users ()`,
+		},
+		{
+			name:    "table constraints",
+			dialect: "postgresql",
+			sql: `CREATE TABLE sales.lines (
+				order_id int NOT NULL,
+				line int NOT NULL,
+				sku text,
+				CONSTRAINT lines_pk PRIMARY KEY (order_id, line),
+				UNIQUE (sku),
+				CONSTRAINT lines_order_fk FOREIGN KEY (order_id) REFERENCES sales.orders (id)
+			);`,
+			table: "sales.lines",
+			want: `order_id int not null
+line int not null
+sku text
+primary lines_pk (order_id, line)
+unique lines_sku_key (sku)
+foreign lines_order_fk (order_id) orders (id)`,
+		},
+		{
+			name:    "foreign keys of other schemas",
+			dialect: "postgresql",
+			sql: `CREATE TABLE public.users (id int PRIMARY KEY);
+				CREATE TABLE sales.users (id int PRIMARY KEY);
+				CREATE TABLE sales.orders (id int PRIMARY KEY, user_id int REFERENCES public.users (id), line_id int REFERENCES sales.lines);
+				ALTER TABLE sales.users RENAME TO customers;
+				ALTER TABLE public.users RENAME TO accounts;`,
+			table: "sales.orders",
+			want: `id int not null
+user_id int
+line_id int
+primary orders_pkey (id)
+foreign orders_user_id_fkey (user_id) public.accounts (id)
+foreign orders_line_id_fkey (line_id) lines ()`,
+		},
+		{
+			name:    "quoted identifiers keep their case",
+			dialect: "postgresql",
+			sql:     `CREATE TABLE "Users" ("ID" int PRIMARY KEY); CREATE TABLE users (id int);`,
+			table:   "Users",
+			want: `ID int not null
+primary Users_pkey (ID)`,
+		},
+		{
+			name:    "inline and table constraints",
+			dialect: "mysql",
+			sql: "CREATE TABLE `users` (\n" +
+				"  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `email` varchar(255) NOT NULL UNIQUE,\n" +
+				"  `team_id` int DEFAULT NULL,\n" +
+				"  `status` enum('a','b') DEFAULT 'a',\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  KEY `team` (`team_id`),\n" +
+				"  CONSTRAINT `users_team` FOREIGN KEY (`team_id`) REFERENCES `teams` (`id`) ON DELETE CASCADE\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+			table: "users",
+			want: `id int unsigned not null auto
+email varchar(255) not null
+team_id int
+status enum default 'a'
+unique email (email)
+primary PRIMARY (id)
+index team (team_id)
+foreign users_team (team_id) teams (id)`,
+		},
+		{
+			name:    "like copies columns and indexes",
+			dialect: "mariadb",
+			sql:     "CREATE TABLE a (id int PRIMARY KEY, name varchar(10)); CREATE TABLE b LIKE a;",
+			table:   "b",
+			want: `id int not null
+name varchar(10)
+primary PRIMARY (id)`,
+		},
+		{
+			name:    "if not exists keeps the first definition",
+			dialect: "sqlite",
+			sql:     "CREATE TABLE a (id INTEGER PRIMARY KEY); CREATE TABLE IF NOT EXISTS a (other text);",
+			table:   "a",
+			want: `id integer not null auto
+primary a_pkey (id)`,
+		},
+		{
+			name:    "identity and computed columns",
+			dialect: "mssql",
+			sql: `CREATE TABLE [dbo].[t] (
+				[id] int IDENTITY(1,1) NOT NULL,
+				[v] AS ([id]*2),
+				[rv] rowversion,
+				CONSTRAINT [PK_t] PRIMARY KEY CLUSTERED ([id] ASC)
+			)
+			GO`,
+			table: "dbo.t",
+			want: `id int not null auto
+v  generated
+rv rowversion generated
+primary PK_t (id)`,
+		},
+		{
+			name:    "identity columns",
+			dialect: "vertica",
+			sql:     "CREATE TABLE public.events (id int IDENTITY(1,1), payload varchar(100) NOT NULL, PRIMARY KEY (id));",
+			table:   "public.events",
+			want: `id int not null auto
+payload varchar(100) not null
+primary events_pkey (id)`,
+		},
+	}
+	for _, test := range tests {
+		table, err := buildTable(test.dialect, test.sql, test.table)
+		if err != nil {
+			t.Errorf("%s %s: %s", test.dialect, test.name, err)
+			continue
+		}
+		if got := tableString(table); got != test.want {
+			t.Errorf("%s %s:\ngot\n%s\nwant\n%s", test.dialect, test.name, got, test.want)
+		}
+	}
+}
+
+func TestSchemaAlter(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		table   string
+		want    string
+	}{
+		{
+			name:    "add column and constraints",
+			dialect: "postgresql",
+			sql: `CREATE TABLE users (id int);
+				ALTER TABLE users ADD COLUMN email text NOT NULL, ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+				ALTER TABLE users ADD COLUMN IF NOT EXISTS email int;
+				ALTER TABLE ONLY users ADD CONSTRAINT users_team FOREIGN KEY (id) REFERENCES teams (id);`,
+			table: "users",
+			want: `id int not null
+email text not null
+primary users_pkey (id)
+foreign users_team (id) teams (id)`,
+		},
+		{
+			name:    "drop column drops its indexes",
+			dialect: "postgresql",
+			sql: `CREATE TABLE users (id int, a int, b int, UNIQUE (a, b));
+				ALTER TABLE users DROP COLUMN b;`,
+			table: "users",
+			want: `id int
+a int`,
+		},
+		{
+			name:    "rename column and table",
+			dialect: "postgresql",
+			sql: `CREATE TABLE users (id int PRIMARY KEY, name text);
+				ALTER TABLE users RENAME COLUMN name TO full_name;
+				ALTER TABLE users RENAME TO accounts;`,
+			table: "accounts",
+			want: `id int not null
+full_name text
+primary users_pkey (id)`,
+		},
+		{
+			name:    "alter column",
+			dialect: "postgresql",
+			sql: `CREATE TABLE users (id int, name varchar(10) NOT NULL DEFAULT 'x');
+				ALTER TABLE users ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY,
+					ALTER COLUMN name TYPE text, ALTER COLUMN name DROP NOT NULL, ALTER COLUMN name DROP DEFAULT;`,
+			table: "users",
+			want: `id int not null auto
+name text`,
+		},
+		{
+			name:    "drop column keeps the rest of the index",
+			dialect: "mysql",
+			sql: "CREATE TABLE users (id int, a int, b int, UNIQUE KEY ab (a, b));\n" +
+				"ALTER TABLE users DROP COLUMN b;",
+			table: "users",
+			want: `id int
+a int
+unique ab (a)`,
+		},
+		{
+			name:    "modify column",
+			dialect: "mysql",
+			sql: "CREATE TABLE users (id int, name varchar(10), email text);\n" +
+				"ALTER TABLE users MODIFY COLUMN name varchar(50) NOT NULL AFTER email;",
+			table: "users",
+			want: `id int
+email text
+name varchar(50) not null`,
+		},
+		{
+			name:    "change column",
+			dialect: "mariadb",
+			sql: "CREATE TABLE users (id int, name varchar(10), KEY name_idx (name));\n" +
+				"ALTER TABLE users CHANGE name full_name varchar(20) FIRST;",
+			table: "users",
+			want: `full_name varchar(20)
+id int
+index name_idx (full_name)`,
+		},
+		{
+			name:    "add columns in parentheses and rename",
+			dialect: "mysql",
+			sql: "CREATE TABLE users (id int);\n" +
+				"ALTER TABLE users ADD (a int, b int), ADD INDEX ab (a, b);\n" +
+				"RENAME TABLE users TO accounts;\n" +
+				"ALTER TABLE accounts RENAME INDEX ab TO a_b;",
+			table: "accounts",
+			want: `id int
+a int
+b int
+index a_b (a, b)`,
+		},
+		{
+			name:    "alter column redefines the type",
+			dialect: "mssql",
+			sql: `CREATE TABLE dbo.users (id int, name varchar(10) NULL);
+				ALTER TABLE dbo.users ALTER COLUMN name nvarchar(20) NOT NULL;
+				ALTER TABLE dbo.users ADD email nvarchar(100);
+				ALTER TABLE dbo.users DROP COLUMN id;`,
+			table: "dbo.users",
+			want: `name nvarchar(20) not null
+email nvarchar(100)`,
+		},
+		{
+			name:    "rename column",
+			dialect: "sqlite",
+			sql: `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+				ALTER TABLE users RENAME COLUMN name TO full_name;
+				ALTER TABLE users ADD COLUMN age INTEGER NOT NULL DEFAULT 0;`,
+			table: "users",
+			want: `id integer not null auto
+full_name text
+age integer not null default 0
+primary users_pkey (id)`,
+		},
+	}
+	for _, test := range tests {
+		table, err := buildTable(test.dialect, test.sql, test.table)
+		if err != nil {
+			t.Errorf("%s %s: %s", test.dialect, test.name, err)
+			continue
+		}
+		if got := tableString(table); got != test.want {
+			t.Errorf("%s %s:\ngot\n%s\nwant\n%s", test.dialect, test.name, got, test.want)
+		}
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+		want    string
+	}{
+		{"postgresql", "CREATE TABLE a (id int);\nALTER TABLE b ADD COLUMN c int;", "line 2: table b does not exist"},
+		{"postgresql", "ALTER TABLE IF EXISTS b ADD COLUMN c int;", ""},
+		{"postgresql", "CREATE TABLE a (id int);\n\nALTER TABLE a ALTER COLUMN c SET NOT NULL;", "line 3: column c does not exist in table a"},
+		{"mysql", "CREATE TABLE a (id int);\nALTER TABLE a RENAME COLUMN id id2;", "line 2: expected TO in rename of column id"},
+		{"mysql", "RENAME TABLE a TO b;", "line 1: table a does not exist"},
+		{"mssql", "CREATE TABLE a (id NOT NULL);", "line 1: column id is missing a type"},
+	}
+	for _, test := range tests {
+		s, err := NewSchema(test.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.Apply(test.sql)
+		if got := ""; err != nil {
+			got = err.Error()
+			if got != test.want {
+				t.Errorf("%s %q: got error %s, want %q", test.dialect, test.sql, got, test.want)
+			}
+		} else if test.want != "" {
+			t.Errorf("%s %q: got no error, want %s", test.dialect, test.sql, test.want)
+		}
+	}
+}
+
+func TestSchemaListTables(t *testing.T) {
+	s, err := NewSchema("postgresql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Apply("CREATE TABLE users (id int); CREATE TABLE sales.orders (id int); CREATE TABLE public.teams (id int); DROP TABLE users;"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		database string
+		want     string
+	}{
+		{"", "public.teams, sales.orders"},
+		{"sales", "orders"},
+		{"public", "teams"},
+	}
+	for _, test := range tests {
+		names, err := s.ListTables(context.Background(), test.database)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(names, ", "); got != test.want {
+			t.Errorf("ListTables(%q) = %s, want %s", test.database, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Snapshot and its methods builds tables from the tables saved by an earlier run, so code can be
+// generated without a connection to the database
+type Snapshot struct {
+	Databases []SnapshotDatabase `json:"databases" yaml:"databases"`
+}
+
+// SnapshotDatabase holds the tables saved from a database, with their definitions before precise
+// types and type overrides are applied
+type SnapshotDatabase struct {
+	Name   string   `json:"name" yaml:"name"`
+	Tables []*Table `json:"tables" yaml:"tables"`
+}
+
+// LoadSnapshot reads a snapshot file, files ending in .yaml or .yml are read as yaml and any other
+// file as json
+func LoadSnapshot(file string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if isYAML(file) {
+		err = yaml.UnmarshalStrict(b, &s)
+	} else {
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.DisallowUnknownFields()
+		err = d.Decode(&s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	return &s, nil
+}
+
+// Save writes the snapshot to the file as yaml or json depending on its extension. Databases and
+// tables are sorted by name so that the file only changes with the schema.
+func (s *Snapshot) Save(file string) error {
+	sort.Slice(s.Databases, func(i, j int) bool { return s.Databases[i].Name < s.Databases[j].Name })
+	for _, d := range s.Databases {
+		sort.Slice(d.Tables, func(i, j int) bool { return d.Tables[i].QualifiedName() < d.Tables[j].QualifiedName() })
+	}
+	var (
+		b   []byte
+		err error
+	)
+	if isYAML(file) {
+		b, err = yaml.Marshal(s)
+	} else {
+		b, err = json.MarshalIndent(s, "", "  ")
+		b = append(b, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
+
+// Add saves a copy of the table in the database, replacing the table of the same schema and name
+func (s *Snapshot) Add(database string, t *Table) {
+	d := s.database(database)
+	if d == nil {
+		s.Databases = append(s.Databases, SnapshotDatabase{Name: database})
+		d = &s.Databases[len(s.Databases)-1]
+	}
+	t = t.copy()
+	for i := range d.Tables {
+		if d.Tables[i].QualifiedName() == t.QualifiedName() {
+			d.Tables[i] = t
+			return
+		}
+	}
+	d.Tables = append(d.Tables, t)
+}
+
+// Build returns a copy of the saved table, table names can be schema qualified
+func (s *Snapshot) Build(ctx context.Context, database, table string) (*Table, error) {
+	d := s.database(database)
+	if d == nil {
+		return nil, fmt.Errorf("database %s is not in the snapshot", database)
+	}
+	for _, t := range d.Tables {
+		if t.QualifiedName() == table {
+			return t.copy(), nil
+		}
+	}
+	for _, t := range d.Tables {
+		if t.Name == table {
+			return t.copy(), nil
+		}
+	}
+	return nil, fmt.Errorf("table %s.%s is not in the snapshot", database, table)
+}
+
+// ListTables returns the names of the saved base tables of the database, names are schema qualified
+// when the tables of the database are from more than one schema
+func (s *Snapshot) ListTables(ctx context.Context, database string) ([]string, error) {
+	return s.list(database, false)
+}
+
+// ListViews returns the names of the saved views of the database, names are schema qualified when
+// the tables of the database are from more than one schema
+func (s *Snapshot) ListViews(ctx context.Context, database string) ([]string, error) {
+	return s.list(database, true)
+}
+
+func (s *Snapshot) list(database string, views bool) ([]string, error) {
+	d := s.database(database)
+	if d == nil {
+		return nil, fmt.Errorf("database %s is not in the snapshot", database)
+	}
+	schemas := map[string]bool{}
+	for _, t := range d.Tables {
+		schemas[t.Schema] = true
+	}
+	var names []string
+	for _, t := range d.Tables {
+		if t.ReadOnly() != views {
+			continue
+		}
+		if len(schemas) > 1 {
+			names = append(names, t.QualifiedName())
+		} else {
+			names = append(names, t.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Snapshot) database(name string) *SnapshotDatabase {
+	for i := range s.Databases {
+		if s.Databases[i].Name == name {
+			return &s.Databases[i]
+		}
+	}
+	return nil
+}
+
+// copy returns a copy of the table that does not share the columns, indexes and foreign keys
+func (t *Table) copy() *Table {
+	c := *t
+	c.Columns = append([]Column(nil), t.Columns...)
+	c.Indexes = append([]Index(nil), t.Indexes...)
+	c.ForeignKeys = append([]ForeignKey(nil), t.ForeignKeys...)
+	return &c
+}
+
+// OpenSnapshot reads the snapshot file, or returns an empty snapshot when the file does not exist yet
+func OpenSnapshot(file string) (*Snapshot, error) {
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return &Snapshot{}, nil
+	}
+	return LoadSnapshot(file)
+}
+
+func isYAML(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Fingerprint returns the sha256 of the json encoding of the tables as built, it only changes with the
+// definitions of the tables whichever machine or snapshot they are built from. The go types of the
+// columns are left out as precise types and type overrides change them, and so are the constraint
+// names the database generates as they differ between databases of the same schema.
+func Fingerprint(tables []*Table) string {
+	var schema []Table
+	for _, t := range tables {
+		c := *t
+		c.Columns = make([]Column, len(t.Columns))
+		for i, column := range t.Columns {
+			column.Definition = ColumnDefinition{}
+			c.Columns[i] = column
+		}
+		c.Indexes = make([]Index, len(t.Indexes))
+		for i, index := range t.Indexes {
+			if generatedName.MatchString(index.Name) {
+				index.Name = ""
+			}
+			c.Indexes[i] = index
+		}
+		c.ForeignKeys = make([]ForeignKey, len(t.ForeignKeys))
+		for i, key := range t.ForeignKeys {
+			if generatedName.MatchString(key.Name) {
+				key.Name = ""
+			}
+			c.ForeignKeys[i] = key
+		}
+		schema = append(schema, c)
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+}
+
+// generatedName matches the constraint names sql server generates for unnamed constraints, a prefix,
+// the table and column names and a random hexadecimal suffix, ex: PK__users__3213E83F0D0A3B5C
+var generatedName = regexp.MustCompile(`^(PK|UQ|FK)__.+__[0-9A-F]{8,16}$`)
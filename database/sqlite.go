@@ -0,0 +1,277 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+)
+
+// SQLite and its methods builds the common table and column structure for formatting
+type SQLite struct {
+	Path string
+}
+
+const (
+	sqliteColumnQuery      = "PRAGMA %s.table_info(%s)"
+	sqliteTableQuery       = "SELECT name FROM %s.sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%%' ORDER BY name"
+	sqliteViewQuery        = "SELECT name FROM %s.sqlite_master WHERE type = 'view' ORDER BY name"
+	sqliteTypeQuery        = "SELECT CASE type WHEN 'view' THEN 'VIEW' ELSE 'BASE TABLE' END FROM %s.sqlite_master WHERE name = ?"
+	sqliteIndexQuery       = "PRAGMA %s.index_list(%s)"
+	sqliteIndexColumnQuery = "PRAGMA %s.index_info(%s)"
+	sqliteForeignKeyQuery  = "PRAGMA %s.foreign_key_list(%s)"
+)
+
+// Build retrieves the table and column schema information for the table and database name to be generated
+// it then parses and converts the database specific types into the correct go types. The database name
+// is the sqlite schema of the table, main for the opened file or the name of an attached database.
+func (s SQLite) Build(ctx context.Context, database, table string) (*Table, error) {
+	db, err := open(ctx, "sqlite3", s.connectionString())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	t := Table{Name: table, Dialect: "sqlite"}
+	if t.Type, err = queryType(ctx, db, fmt.Sprintf(sqliteTypeQuery, sqliteQuote(database)), table); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(sqliteColumnQuery, sqliteQuote(database), sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	// primary key columns are numbered by their position in the key
+	primaryKey := map[int]string{}
+	for rows.Next() {
+		var (
+			c                Column
+			cid, notNull, pk int
+			declared         string
+			defaultValue     sql.NullString
+		)
+		err := rows.Scan(&cid, &c.Name, &declared, &notNull, &defaultValue, &pk)
+		if err != nil {
+			return nil, err
+		}
+
+		c.DatabaseType = strings.ToLower(strings.TrimSpace(declared))
+		c.stripSize()
+		c.DatabaseType, c.Definition = sqliteDefinition(c.DatabaseType)
+		if defaultValue.Valid {
+			c.Default = &defaultValue.String
+		}
+		// only integer primary keys are aliases of the rowid and can never be null
+		c.DatabaseNullable = "YES"
+		if notNull == 1 || (pk > 0 && c.DatabaseType == "integer") {
+			c.DatabaseNullable = "NO"
+		}
+		if pk > 0 {
+			primaryKey[pk] = c.Name
+		}
+		t.Columns = append(t.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("No rows returned from table_info for schema %s and table %s", database, table)
+	}
+	if len(primaryKey) > 0 {
+		pk := Index{Name: "PRIMARY", Primary: true, Unique: true}
+		for i := 1; i <= len(primaryKey); i++ {
+			pk.Columns = append(pk.Columns, primaryKey[i])
+		}
+		t.Indexes = append(t.Indexes, pk)
+		// a single integer primary key is an alias of the rowid and is filled in on insert
+		if c := findColumn(&t, primaryKey[1]); len(primaryKey) == 1 && c.DatabaseType == "integer" {
+			c.AutoIncrement = true
+		}
+	}
+	indexes, err := sqliteIndexes(ctx, db, database, table)
+	if err != nil {
+		return nil, err
+	}
+	t.Indexes = append(t.Indexes, indexes...)
+	if t.ForeignKeys, err = sqliteForeignKeys(ctx, db, database, table); err != nil {
+		return nil, err
+	}
+	t.markKeys()
+	return &t, nil
+}
+
+// ListTables returns the names of the tables in the sqlite schema, excluding internal sqlite tables
+func (s SQLite) ListTables(ctx context.Context, database string) ([]string, error) {
+	db, err := open(ctx, "sqlite3", s.connectionString())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(sqliteTableQuery, sqliteQuote(database)))
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// ListViews returns the names of the views in the sqlite schema
+func (s SQLite) ListViews(ctx context.Context, database string) ([]string, error) {
+	db, err := open(ctx, "sqlite3", s.connectionString())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(sqliteViewQuery, sqliteQuote(database)))
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// sqliteIndexes reads the unique constraints and indexes of the table. The primary key is read
+// from the table info as integer primary keys have no index.
+func sqliteIndexes(ctx context.Context, db *sql.DB, database, table string) ([]Index, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(sqliteIndexQuery, sqliteQuote(database), sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var indexes []Index
+	for rows.Next() {
+		var (
+			seq, unique, partial int
+			name, origin         string
+		)
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			return nil, err
+		}
+		if origin != "pk" {
+			indexes = append(indexes, Index{Name: name, Unique: unique == 1})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range indexes {
+		columns, err := db.QueryContext(ctx, fmt.Sprintf(sqliteIndexColumnQuery, sqliteQuote(database), sqliteQuote(indexes[i].Name)))
+		if err != nil {
+			return nil, err
+		}
+		for columns.Next() {
+			var (
+				seqno, cid int
+				name       sql.NullString
+			)
+			if err := columns.Scan(&seqno, &cid, &name); err != nil {
+				columns.Close()
+				return nil, err
+			}
+			// expressions of expression indexes have no column name
+			if name.Valid {
+				indexes[i].Columns = append(indexes[i].Columns, name.String)
+			}
+		}
+		columns.Close()
+		if err := columns.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return indexes, nil
+}
+
+// sqliteForeignKeys reads the foreign keys of the table. Sqlite does not name foreign keys so they
+// are named after the table and their columns.
+func sqliteForeignKeys(ctx context.Context, db *sql.DB, database, table string) ([]ForeignKey, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(sqliteForeignKeyQuery, sqliteQuote(database), sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []ForeignKey
+	ids := map[int]int{}
+	for rows.Next() {
+		var (
+			id, seq                       int
+			referencedTable, from         string
+			to                            sql.NullString
+			onUpdate, onDelete, matchType string
+		)
+		if err := rows.Scan(&id, &seq, &referencedTable, &from, &to, &onUpdate, &onDelete, &matchType); err != nil {
+			return nil, err
+		}
+		i, ok := ids[id]
+		if !ok {
+			i = len(keys)
+			ids[id] = i
+			keys = append(keys, ForeignKey{ReferencedTable: referencedTable})
+		}
+		keys[i].Columns = append(keys[i].Columns, from)
+		// the referenced columns are omitted when the primary key is referenced
+		if to.Valid {
+			keys[i].ReferencedColumns = append(keys[i].ReferencedColumns, to.String)
+		}
+	}
+	for i := range keys {
+		keys[i].Name = table + "_" + strings.Join(keys[i].Columns, "_") + "_fkey"
+	}
+	return keys, rows.Err()
+}
+
+func (s *SQLite) connectionString() string {
+	// ex: "file:example.db?mode=ro"
+	return fmt.Sprintf("file:%s?mode=ro", s.Path)
+}
+
+// sqliteQuote quotes an identifier for use in a pragma statement
+func sqliteQuote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// sqliteDefinition normalizes the declared column type and maps it to its column definition.
+// Declared types without an explicit mapping are resolved using the sqlite type affinity rules
+// https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+func sqliteDefinition(declared string) (string, ColumnDefinition) {
+	// remove sqlite sizes
+	r := regexp.MustCompile("\\s*\\(.*\\)")
+	databaseType := strings.ToLower(strings.TrimSpace(r.ReplaceAllString(declared, "")))
+
+	if d, ok := sqliteTypeMap[databaseType]; ok {
+		return databaseType, d
+	}
+	switch {
+	case strings.Contains(databaseType, "int"):
+		return databaseType, sqliteAffinityMap["integer"]
+	case strings.Contains(databaseType, "char"), strings.Contains(databaseType, "clob"), strings.Contains(databaseType, "text"):
+		return databaseType, sqliteAffinityMap["text"]
+	case strings.Contains(databaseType, "blob"), databaseType == "":
+		return databaseType, sqliteAffinityMap["blob"]
+	case strings.Contains(databaseType, "real"), strings.Contains(databaseType, "floa"), strings.Contains(databaseType, "doub"):
+		return databaseType, sqliteAffinityMap["real"]
+	}
+	return databaseType, sqliteAffinityMap["numeric"]
+}
+
+// sqliteTypeMap holds declared types that the sqlite driver converts to a more specific go type
+// than their affinity
+var sqliteTypeMap = map[string]ColumnDefinition{
+	// time fields
+	"date":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"datetime":  ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamp": ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	// bool
+	"bool":    ColumnDefinition{GoType: "bool", GureguType: "null.Bool", SQLType: "sql.NullBool"},
+	"boolean": ColumnDefinition{GoType: "bool", GureguType: "null.Bool", SQLType: "sql.NullBool"},
+}
+
+var sqliteAffinityMap = map[string]ColumnDefinition{
+	"integer": ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"text":    ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"blob":    ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"real":    ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"numeric": ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+}
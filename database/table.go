@@ -0,0 +1,223 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// TableType is the kind of relation a table was built from
+type TableType string
+
+const (
+	// BaseTable is a regular writable table
+	BaseTable TableType = "BASE TABLE"
+	// View is a read only view
+	View TableType = "VIEW"
+	// MaterializedView is a read only view whose result is stored
+	MaterializedView TableType = "MATERIALIZED VIEW"
+)
+
+type (
+	// Table contains all column definitions
+	Table struct {
+		Name string `json:"name" yaml:"name"`
+		// Schema is the schema of the table for databases with schemas such as postgresql
+		Schema      string       `json:"schema,omitempty" yaml:"schema,omitempty"`
+		Type        TableType    `json:"type" yaml:"type"`
+		Dialect     string       `json:"dialect" yaml:"dialect"`
+		Columns     []Column     `json:"columns" yaml:"columns"`
+		Indexes     []Index      `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+		ForeignKeys []ForeignKey `json:"foreign_keys,omitempty" yaml:"foreign_keys,omitempty"`
+	}
+
+	// Column contains the necessary information to generate the column struct field
+	Column struct {
+		Name string `json:"name" yaml:"name"`
+		// Database representation
+		DatabaseType     string           `json:"database_type" yaml:"database_type"`
+		DatabaseNullable string           `json:"database_nullable" yaml:"database_nullable"`
+		Definition       ColumnDefinition `json:"definition,omitempty" yaml:"definition,omitempty"`
+		// Length is the maximum length of character and binary types, -1 for unlimited types such as varchar(max)
+		Length int `json:"length,omitempty" yaml:"length,omitempty"`
+		// Precision and Scale are the number of digits of decimal types
+		Precision int `json:"precision,omitempty" yaml:"precision,omitempty"`
+		Scale     int `json:"scale,omitempty" yaml:"scale,omitempty"`
+		// Default is the default expression of the column, string literals quoted, nil without a default
+		Default  *string `json:"default,omitempty" yaml:"default,omitempty"`
+		Unsigned bool    `json:"unsigned,omitempty" yaml:"unsigned,omitempty"`
+		// AutoIncrement is set for serial, identity and auto increment columns the database fills in
+		AutoIncrement bool `json:"auto_increment,omitempty" yaml:"auto_increment,omitempty"`
+		// Generated is set for generated, computed and row version columns the database computes
+		Generated bool `json:"generated,omitempty" yaml:"generated,omitempty"`
+		// Keys the column is part of
+		PrimaryKey bool `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+		Unique     bool `json:"unique,omitempty" yaml:"unique,omitempty"`
+	}
+
+	// Index contains the columns of a primary key, unique constraint or index in key order
+	Index struct {
+		Name    string   `json:"name" yaml:"name"`
+		Columns []string `json:"columns" yaml:"columns"`
+		Primary bool     `json:"primary,omitempty" yaml:"primary,omitempty"`
+		Unique  bool     `json:"unique,omitempty" yaml:"unique,omitempty"`
+	}
+
+	// ForeignKey contains the columns of a foreign key and the columns of the table they reference in key order
+	ForeignKey struct {
+		Name            string   `json:"name" yaml:"name"`
+		Columns         []string `json:"columns" yaml:"columns"`
+		ReferencedTable string   `json:"referenced_table" yaml:"referenced_table"`
+		// ReferencedSchema is the schema of the referenced table when it is not the schema of the table
+		ReferencedSchema string `json:"referenced_schema,omitempty" yaml:"referenced_schema,omitempty"`
+		// ReferencedColumns is empty when the primary key of the referenced table is referenced
+		ReferencedColumns []string `json:"referenced_columns,omitempty" yaml:"referenced_columns,omitempty"`
+	}
+
+	// ColumnDefinition contains the necessary information for the struct field type
+	ColumnDefinition struct {
+		// Used for creation of the struct
+		GoType     string `json:"go_type" yaml:"go_type"`
+		GureguType string `json:"guregu_type,omitempty" yaml:"guregu_type,omitempty"`
+		SQLType    string `json:"sql_type,omitempty" yaml:"sql_type,omitempty"`
+		// Import is the package of the types that goimports can not resolve on its own
+		Import string `json:"import,omitempty" yaml:"import,omitempty"`
+	}
+)
+
+// sizedTypes are the types written with their length, ex: varchar(100)
+var sizedTypes = map[string]bool{
+	"char": true, "varchar": true, "character": true, "character varying": true, "nchar": true, "nvarchar": true,
+	"binary": true, "varbinary": true, "long varchar": true, "long varbinary": true, "bit": true, "bit varying": true,
+}
+
+// decimalTypes are the types written with their precision and scale, ex: decimal(10,2)
+var decimalTypes = map[string]bool{"decimal": true, "numeric": true, "number": true, "dec": true, "fixed": true}
+
+// FullType returns the database type including its length or precision and scale, ex: varchar(100) or numeric(10,2)
+func (c Column) FullType() string {
+	switch {
+	case sizedTypes[c.DatabaseType] && c.Length == -1:
+		return c.DatabaseType + "(max)"
+	case sizedTypes[c.DatabaseType] && c.Length > 0:
+		return fmt.Sprintf("%s(%d)", c.DatabaseType, c.Length)
+	case decimalTypes[c.DatabaseType] && c.Precision > 0:
+		return fmt.Sprintf("%s(%d,%d)", c.DatabaseType, c.Precision, c.Scale)
+	}
+	return c.DatabaseType
+}
+
+var (
+	lengthSize    = regexp.MustCompile("\\(([0-9]+)\\)")
+	precisionSize = regexp.MustCompile("\\(([0-9]+),([0-9]+)\\)")
+)
+
+// stripSize removes the size written in the database type, ex: numeric(10,2) to numeric, and keeps it
+// as the length or precision and scale of the column unless the catalog already reported them
+func (c *Column) stripSize() {
+	if m := precisionSize.FindStringSubmatch(c.DatabaseType); m != nil {
+		if c.Precision == 0 {
+			c.Precision, _ = strconv.Atoi(m[1])
+			c.Scale, _ = strconv.Atoi(m[2])
+		}
+		c.DatabaseType = precisionSize.ReplaceAllString(c.DatabaseType, "")
+	}
+	if m := lengthSize.FindStringSubmatch(c.DatabaseType); m != nil {
+		size, _ := strconv.Atoi(m[1])
+		c.DatabaseType = lengthSize.ReplaceAllString(c.DatabaseType, "")
+		if decimalTypes[c.DatabaseType] && c.Precision == 0 {
+			c.Precision = size
+		} else if sizedTypes[c.DatabaseType] && c.Length == 0 {
+			c.Length = size
+		}
+	}
+}
+
+// ReadOnly reports whether the table is a view that can not be written to
+func (t *Table) ReadOnly() bool {
+	return t.Type == View || t.Type == MaterializedView
+}
+
+// QualifiedName returns the table name qualified by its schema when the table has a schema
+func (t *Table) QualifiedName() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// tableType converts the table type reported by a catalog, ex: BASE TABLE, VIEW, SYSTEM VIEW
+func tableType(catalogType string) TableType {
+	switch catalogType {
+	case "VIEW", "SYSTEM VIEW":
+		return View
+	case "MATERIALIZED VIEW":
+		return MaterializedView
+	}
+	return BaseTable
+}
+
+// PrimaryKey returns the primary key columns of the table in key order
+func (t *Table) PrimaryKey() []string {
+	for _, i := range t.Indexes {
+		if i.Primary {
+			return i.Columns
+		}
+	}
+	return nil
+}
+
+// UniqueKeys returns the primary key followed by the unique indexes of the table
+func (t *Table) UniqueKeys() []Index {
+	var keys []Index
+	for _, i := range t.Indexes {
+		if i.Primary {
+			keys = append([]Index{i}, keys...)
+		} else if i.Unique {
+			keys = append(keys, i)
+		}
+	}
+	return keys
+}
+
+// markKeys flags the columns of the primary key and of single column unique indexes
+func (t *Table) markKeys() {
+	for _, i := range t.Indexes {
+		for _, name := range i.Columns {
+			for c := range t.Columns {
+				if t.Columns[c].Name != name {
+					continue
+				}
+				if i.Primary {
+					t.Columns[c].PrimaryKey = true
+				}
+				if i.Unique && !i.Primary && len(i.Columns) == 1 {
+					t.Columns[c].Unique = true
+				}
+			}
+		}
+	}
+}
+
+// UnknownTypeError is returned for a column of a type without a go type
+type UnknownTypeError struct {
+	Table, Column, DatabaseType string
+}
+
+func (e *UnknownTypeError) Error() string {
+	if e.DatabaseType == "" {
+		return fmt.Sprintf("Unrecognized column type field: %s.%s has no type, computed columns need a type override", e.Table, e.Column)
+	}
+	return fmt.Sprintf("Unrecognized column type field: %s of %s.%s", e.DatabaseType, e.Table, e.Column)
+}
+
+// Resolved returns an UnknownTypeError for the first column without a go type, columns of
+// unrecognized types need a type override
+func (t *Table) Resolved() error {
+	for _, c := range t.Columns {
+		if c.Definition.GoType == "" {
+			return &UnknownTypeError{Table: t.Name, Column: c.Name, DatabaseType: c.DatabaseType}
+		}
+	}
+	return nil
+}
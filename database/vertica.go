@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/alexbrainman/odbc" // odbc connection driver for vertica data sources
+)
+
+// Vertica and its methods builds the common table and column structure for formatting. The server of
+// the connection options is reached with the native vertica driver, or with the vertica odbc driver
+// when ODBC is set. An odbc data source is used instead when DSN is set.
+type Vertica struct {
+	// DSN is the name of an odbc data source
+	DSN  string
+	ODBC bool
+	Connection
+}
+
+const (
+	// nullability is read as YES or NO as the drivers return booleans differently
+	verticaColumnQuery = "SELECT column_name, data_type, CASE WHEN is_nullable THEN 'YES' ELSE 'NO' END, character_maximum_length, numeric_precision, numeric_scale, column_default, is_identity FROM v_catalog.columns WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position"
+	verticaTableQuery  = "SELECT table_name FROM v_catalog.tables WHERE table_schema = ? ORDER BY table_name"
+	verticaViewQuery   = "SELECT table_name FROM v_catalog.views WHERE table_schema = ? ORDER BY table_name"
+	verticaTypeQuery   = "SELECT 'VIEW' FROM v_catalog.views WHERE table_schema = ? AND table_name = ?"
+	// vertica has no indexes, primary keys and unique constraints are read from the constraints
+	verticaIndexQuery      = "SELECT constraint_name, CASE WHEN constraint_type = 'p' THEN 1 ELSE 0 END, 1, column_name FROM v_catalog.constraint_columns WHERE table_schema = ? AND table_name = ? AND constraint_type IN ('p', 'u') ORDER BY constraint_name"
+	verticaForeignKeyQuery = "SELECT constraint_name, column_name, reference_table_name, reference_column_name, reference_table_schema FROM v_catalog.foreign_keys WHERE table_schema = ? AND table_name = ? ORDER BY constraint_name, ordinal_position"
+	// view columns do not report their nullability
+	verticaViewColumnQuery = "SELECT column_name, data_type, 'YES', character_maximum_length, numeric_precision, numeric_scale, NULL, false FROM v_catalog.view_columns WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position"
+)
+
+// Build retrieves the table and column schema information for the table and database name to be generated
+// it then parses and converts the database specific types into the correct go types
+func (v Vertica) Build(ctx context.Context, database, table string) (*Table, error) {
+	db, err := v.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	t := Table{Name: table, Dialect: "vertica"}
+	if t.Type, err = queryType(ctx, db, verticaTypeQuery, database, table); err != nil {
+		return nil, err
+	}
+	query := verticaColumnQuery
+	if t.Type == View {
+		query = verticaViewColumnQuery
+	}
+	rows, err := db.QueryContext(ctx, query, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var identity bool
+		c, err := scanColumn(rows, &identity)
+		if err != nil {
+			return nil, err
+		}
+		c.AutoIncrement = identity
+
+		// move vertica sizes to the length, precision and scale
+		c.stripSize()
+
+		// unrecognized types are left without a definition for type overrides
+		c.Definition = verticaTypeMap[c.DatabaseType]
+		t.Columns = append(t.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("No rows returned from the information schema for TABLE_SCHEMA %s and TABLE_NAME %s", database, table)
+	}
+	if t.Indexes, err = queryIndexes(ctx, db, verticaIndexQuery, database, table); err != nil {
+		return nil, err
+	}
+	if t.ForeignKeys, err = queryForeignKeys(ctx, db, verticaForeignKeyQuery, database, table); err != nil {
+		return nil, err
+	}
+	t.markKeys()
+	return &t, nil
+}
+
+// ListTables returns the names of the tables in the database
+func (v Vertica) ListTables(ctx context.Context, database string) ([]string, error) {
+	db, err := v.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, verticaTableQuery, database)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// ListViews returns the names of the views in the database
+func (v Vertica) ListViews(ctx context.Context, database string) ([]string, error) {
+	db, err := v.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, verticaViewQuery, database)
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)
+}
+
+// open connects to the odbc data source, or to the server of the connection options
+func (v Vertica) open(ctx context.Context) (*sql.DB, error) {
+	if v.DSN != "" {
+		// ex: "Driver={odbc};Servername=example;Database=example;Port=5433;uid=dbadmin;pwd=test;ResultBufferSize=0;ConnectionLoadBalance=1;"
+		return open(ctx, "odbc", fmt.Sprintf("DSN=%s;ResultBufferSize=0;ConnectionLoadBalance=1;", v.DSN))
+	}
+	if v.ODBC {
+		dsn, err := v.verticaODBC()
+		if err != nil {
+			return nil, err
+		}
+		return open(ctx, "odbc", dsn+"ResultBufferSize=0;ConnectionLoadBalance=1;")
+	}
+	dsn, err := v.verticaDSN()
+	if err != nil {
+		return nil, err
+	}
+	return open(ctx, "vertica", dsn)
+}
+
+var verticaTypeMap = map[string]ColumnDefinition{
+	// integer fields
+	"tinyint":   ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"int":       ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"smallint":  ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"mediumint": ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	"bigint":    ColumnDefinition{GoType: "int", GureguType: "null.Int", SQLType: "sql.NullInt64"},
+	// string fields
+	"char":              ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"character":         ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"varchar":           ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"character varying": ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	"long varchar":      ColumnDefinition{GoType: "string", GureguType: "null.String", SQLType: "sql.NullString"},
+	// time fields
+	"date":          ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"datetime":      ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"smalldatetime": ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"time":          ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	"timestamp":     ColumnDefinition{GoType: "time.Time", GureguType: "null.Time", SQLType: "mysql.NullTime"},
+	// float fields
+	"float":            ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float8":           ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float16":          ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float32":          ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"float64":          ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"numeric":          ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"number":           ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"double precision": ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	"real precision":   ColumnDefinition{GoType: "float64", GureguType: "null.Float", SQLType: "sql.NullFloat64"},
+	// binary fields
+	"binary":         ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"varbinary":      ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"binary varying": ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"bytea":          ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	"raw":            ColumnDefinition{GoType: "[]byte", GureguType: "[]byte", SQLType: "[]byte"},
+	// bool
+	"boolean": ColumnDefinition{GoType: "bool", GureguType: "null.Bool", SQLType: "null.Bool"},
+}
@@ -0,0 +1,109 @@
+// Package gostructify generates go structs and methods from the tables of a database. It is the
+// pipeline of the gostructify command, for use in other tools:
+//
+//	src, err := gostructify.Generate(ctx, database.SQLite{Path: "app.db"}, gostructify.Options{
+//		Database: "main",
+//		Tables:   []string{"*"},
+//		Package:  "models",
+//		Struct:   structify.Options{Tags: []string{"json", "sqlx"}},
+//	})
+package gostructify
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/snagles/gostructify/database"
+	"github.com/snagles/gostructify/structify"
+	"golang.org/x/tools/imports"
+)
+
+// Options select the tables of a database and how they are generated
+type Options struct {
+	Database string
+	// Tables are table names or glob patterns such as * or audit_*
+	Tables []string
+	// ExcludeTables are regular expressions of table names to leave out
+	ExcludeTables []string
+	// Views includes views and materialized views in table patterns
+	Views bool
+	// Precise maps integers to the go type of their size and decimals to the Decimal type,
+	// shopspring, apd or string
+	Precise bool
+	Decimal string
+	Types   database.TypeOverrides
+	Struct  structify.Options
+
+	Package string
+	// Command is recorded in the code generated header, it should hold no secret such as a password
+	Command string
+	// Output is the file the source is written to, go files are formatted with goimports
+	// resolving packages from its directory. Other files are left as generated by the templates.
+	Output string
+	// Snapshot saves the tables as built from the database when set
+	Snapshot *database.Snapshot
+}
+
+// Generate builds the selected tables of the database and returns the generated source
+func Generate(ctx context.Context, d database.Database, o Options) ([]byte, error) {
+	tables, err := SelectTables(ctx, d, o.Database, o.Tables, o.ExcludeTables, o.Views)
+	if err != nil {
+		return nil, err
+	}
+	built, err := Build(ctx, d, o, tables)
+	if err != nil {
+		return nil, err
+	}
+	src, err := structify.Generate(o.Struct, structify.File{Package: o.Package, Database: o.Database, Command: o.Command, Tables: built})
+	if err != nil {
+		return nil, fmt.Errorf("generating %s: %w", o.Database, err)
+	}
+	return Format(o.Output, src)
+}
+
+// Build builds the tables of the database with their precise types and type overrides applied,
+// the tables are saved to the snapshot of the options before they are applied
+func Build(ctx context.Context, d database.Database, o Options, tables []string) ([]*database.Table, error) {
+	var built []*database.Table
+	for _, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		t, err := d.Build(ctx, o.Database, table)
+		if err != nil {
+			return nil, fmt.Errorf("building %s.%s: %w", o.Database, table, err)
+		}
+		if o.Snapshot != nil {
+			o.Snapshot.Add(o.Database, t)
+		}
+		if o.Precise {
+			decimal := o.Decimal
+			if decimal == "" {
+				decimal = "shopspring"
+			}
+			if err := t.Precise(decimal); err != nil {
+				return nil, fmt.Errorf("building %s.%s: %w", o.Database, table, err)
+			}
+		}
+		o.Types.Apply(t)
+		if err := t.Resolved(); err != nil {
+			return nil, fmt.Errorf("building %s.%s: %w", o.Database, table, err)
+		}
+		built = append(built, t)
+	}
+	return built, nil
+}
+
+// Format runs goimports to gofmt and goimports go output files, the output of other templates such
+// as protobuf files is returned as is
+func Format(output string, src []byte) ([]byte, error) {
+	if output != "" && filepath.Ext(output) != ".go" {
+		return src, nil
+	}
+	formatted, err := imports.Process(output, src, nil)
+	if err != nil {
+		return nil, fmt.Errorf("processing imports: %s", err)
+	}
+	return formatted, nil
+}
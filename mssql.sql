@@ -0,0 +1,67 @@
+IF DB_ID('test') IS NULL CREATE DATABASE test;
+GO
+USE test;
+GO
+DROP TABLE IF EXISTS dbo.all_data_types;
+CREATE TABLE dbo.all_data_types (
+  bigintnull bigint NULL,
+  bigint bigint NOT NULL,
+  intnull int NULL,
+  int int NOT NULL,
+  smallintnull smallint NULL,
+  smallint smallint NOT NULL,
+  tinyintnull tinyint NULL,
+  tinyint tinyint NOT NULL,
+  bitnull bit NULL,
+  bit bit NOT NULL,
+  decimalnull decimal(10, 2) NULL,
+  decimal decimal(10, 2) NOT NULL,
+  numericnull numeric(10, 2) NULL,
+  numeric numeric(10, 2) NOT NULL,
+  moneynull money NULL,
+  money money NOT NULL,
+  smallmoneynull smallmoney NULL,
+  smallmoney smallmoney NOT NULL,
+  floatnull float NULL,
+  float float NOT NULL,
+  realnull real NULL,
+  real real NOT NULL,
+  datenull date NULL,
+  date date NOT NULL,
+  datetimenull datetime NULL,
+  datetime datetime NOT NULL,
+  datetime2null datetime2 NULL,
+  datetime2 datetime2 NOT NULL,
+  datetimeoffsetnull datetimeoffset NULL,
+  datetimeoffset datetimeoffset NOT NULL,
+  smalldatetimenull smalldatetime NULL,
+  smalldatetime smalldatetime NOT NULL,
+  timenull time NULL,
+  time time NOT NULL,
+  charnull char(10) NULL,
+  char char(10) NOT NULL,
+  varcharnull varchar(20) NULL,
+  varchar varchar(20) NOT NULL,
+  textnull text NULL,
+  text text NOT NULL,
+  ncharnull nchar(10) NULL,
+  nchar nchar(10) NOT NULL,
+  nvarcharnull nvarchar(20) NULL,
+  nvarchar nvarchar(20) NOT NULL,
+  ntextnull ntext NULL,
+  ntext ntext NOT NULL,
+  xmlnull xml NULL,
+  xml xml NOT NULL,
+  binarynull binary(20) NULL,
+  binary binary(20) NOT NULL,
+  varbinarynull varbinary(20) NULL,
+  varbinary varbinary(20) NOT NULL,
+  imagenull image NULL,
+  image image NOT NULL,
+  rowversion rowversion NOT NULL,
+  uniqueidentifiernull uniqueidentifier NULL,
+  uniqueidentifier uniqueidentifier NOT NULL,
+  sql_variantnull sql_variant NULL,
+  sql_variant sql_variant NOT NULL
+);
+GO
@@ -0,0 +1,36 @@
+DROP TABLE IF EXISTS all_data_types;
+CREATE TABLE all_data_types (
+  integernull INTEGER NULL,
+  integer INTEGER NOT NULL,
+  intnull INT NULL,
+  int INT NOT NULL,
+  bigintnull BIGINT NULL,
+  bigint BIGINT NOT NULL,
+  textnull TEXT NULL,
+  text TEXT NOT NULL,
+  varcharnull VARCHAR(20) NULL,
+  varchar VARCHAR(20) NOT NULL,
+  clobnull CLOB NULL,
+  clob CLOB NOT NULL,
+  blobnull BLOB NULL,
+  blob BLOB NOT NULL,
+  untypednull NULL,
+  realnull REAL NULL,
+  real REAL NOT NULL,
+  doublenull DOUBLE NULL,
+  double DOUBLE NOT NULL,
+  floatnull FLOAT NULL,
+  float FLOAT NOT NULL,
+  numericnull NUMERIC NULL,
+  numeric NUMERIC NOT NULL,
+  decimalnull DECIMAL(10, 2) NULL,
+  decimal DECIMAL(10, 2) NOT NULL,
+  datenull DATE NULL,
+  date DATE NOT NULL,
+  datetimenull DATETIME NULL,
+  datetime DATETIME NOT NULL,
+  timestampnull TIMESTAMP NULL,
+  timestamp TIMESTAMP NOT NULL,
+  booleannull BOOLEAN NULL,
+  boolean BOOLEAN NOT NULL
+);
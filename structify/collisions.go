@@ -0,0 +1,183 @@
+package structify
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"text/template"
+
+	"github.com/snagles/gostructify/database"
+)
+
+// resolveNames returns the options with renames for the struct, field and function names that
+// collide, so the generated file always compiles. Tables and columns keep their order, the first keeps
+// its name and the later ones are suffixed with the lowest free number, ex: user_id and userId generate
+// UserID and UserID2. Fields also give way to the methods of the struct, such as the gorm TableName,
+// and the functions of a key, such as the sqlx FindUserByID, to the structs and earlier functions.
+func (o Options) resolveNames(tmpl *template.Template, dbName string, tables []*database.Table) (Options, error) {
+	n := o.Naming
+	n.Tables = copyNames(n.Tables)
+	n.Columns = copyNames(n.Columns)
+
+	structs := map[string]string{}
+	for _, t := range tables {
+		name := n.structName(t.Schema, t.Name)
+		if other, ok := structs[name]; ok {
+			resolved := freeName(name, structs)
+			o.warnf("Struct name %s of table %s collides with %s, it is renamed %s", name, t.QualifiedName(), other, resolved)
+			n.Tables[t.QualifiedName()] = resolved
+			name = resolved
+		}
+		structs[name] = "table " + t.QualifiedName()
+	}
+	o.Naming = n
+
+	for _, t := range tables {
+		fields := map[string]string{}
+		methods, _ := o.declaredNames(tmpl, o.newStruct(dbName, t, tables))
+		for _, method := range methods {
+			fields[method] = "method " + method
+		}
+		for _, c := range t.Columns {
+			name := n.fieldName(t.QualifiedName(), c.Name)
+			if other, ok := fields[name]; ok {
+				resolved := freeName(name, fields)
+				o.warnf("Field name %s of column %s.%s collides with %s, it is renamed %s", name, t.QualifiedName(), c.Name, other, resolved)
+				n.Columns[t.QualifiedName()+"."+c.Name] = resolved
+				name = resolved
+			}
+			fields[name] = "column " + c.Name
+		}
+	}
+	o.Naming = n
+
+	// functions share the package scope with the structs, the functions declared without a key can
+	// not be renamed while the functions of a key are renamed with the key name
+	o.keyNames = map[string]string{}
+	declared := copyNames(structs)
+	for _, t := range tables {
+		s := o.newStruct(dbName, t, tables)
+		keys := s.Keys
+		s.Keys = nil
+		_, functions := o.declaredNames(tmpl, s)
+		for _, function := range functions {
+			if other, ok := declared[function]; ok {
+				return o, fmt.Errorf("Function %s of table %s collides with %s", function, t.QualifiedName(), other)
+			}
+			declared[function] = "function of table " + t.QualifiedName()
+		}
+		base := map[string]bool{}
+		for _, function := range functions {
+			base[function] = true
+		}
+		for _, key := range keys {
+			name := key.Name
+			var keyFunctions []string
+			for i := 2; ; i++ {
+				s.Keys = []Key{key}
+				previous := strings.Join(keyFunctions, ",")
+				_, keyFunctions = o.declaredNames(tmpl, s)
+				other := collision(keyFunctions, base, declared)
+				if other == "" {
+					break
+				}
+				// functions that do not hold the key name can not be renamed
+				if i > 2 && strings.Join(keyFunctions, ",") == previous {
+					return o, fmt.Errorf("Functions of key %s of table %s collide with %s", name, t.QualifiedName(), other)
+				}
+				key.Name = fmt.Sprintf("%s%d", name, i)
+			}
+			if key.Name != name {
+				o.warnf("Functions of key %s of table %s collide, the key is renamed %s", name, t.QualifiedName(), key.Name)
+				o.keyNames[keyID(t, key.Index)] = key.Name
+			}
+			for _, function := range keyFunctions {
+				if !base[function] {
+					declared[function] = "function of key " + key.Name + " of table " + t.QualifiedName()
+				}
+			}
+		}
+	}
+	return o, nil
+}
+
+// collision returns the declaration a function of the key collides with, functions declared without
+// the key are skipped
+func collision(functions []string, base map[string]bool, declared map[string]string) string {
+	seen := map[string]bool{}
+	for _, function := range functions {
+		if base[function] {
+			continue
+		}
+		if other, ok := declared[function]; ok {
+			return other
+		}
+		if seen[function] {
+			return "function " + function
+		}
+		seen[function] = true
+	}
+	return ""
+}
+
+// keyID identifies a key of a table by its columns
+func keyID(t *database.Table, index database.Index) string {
+	return t.QualifiedName() + "(" + strings.Join(index.Columns, ",") + ")"
+}
+
+// warnf passes the formatted message to the Warn function of the options
+func (o Options) warnf(format string, args ...interface{}) {
+	if o.Warn != nil {
+		o.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
+// declaredNames returns the names of the methods and the functions the method templates declare for
+// the struct. Templates whose output can not be parsed as go, such as templates for other languages,
+// are skipped.
+func (o Options) declaredNames(tmpl *template.Template, s Struct) (methods, functions []string) {
+	for _, method := range o.Methods {
+		if tmpl.Lookup(method) == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, method, s); err != nil {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+buf.String(), 0)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			// init and blank functions can be declared any number of times
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name != "init" && fn.Name.Name != "_" {
+				if fn.Recv != nil {
+					methods = append(methods, fn.Name.Name)
+				} else {
+					functions = append(functions, fn.Name.Name)
+				}
+			}
+		}
+	}
+	return methods, functions
+}
+
+// freeName returns the name suffixed with the lowest number from 2 that is not taken
+func freeName(name string, taken map[string]string) string {
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s%d", name, i); taken[candidate] == "" {
+			return candidate
+		}
+	}
+}
+
+func copyNames(names map[string]string) map[string]string {
+	c := map[string]string{}
+	for k, v := range names {
+		c[k] = v
+	}
+	return c
+}
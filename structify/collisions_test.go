@@ -0,0 +1,52 @@
+package structify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/snagles/gostructify/database"
+)
+
+func TestFunctionCollisions(t *testing.T) {
+	column := func(name string) database.Column {
+		return database.Column{Name: name, DatabaseType: "bigint", DatabaseNullable: "NO", Definition: database.ColumnDefinition{GoType: "int64"}}
+	}
+	users := &database.Table{
+		Name:    "users",
+		Type:    database.BaseTable,
+		Dialect: "mysql",
+		Columns: []database.Column{column("id"), column("name"), column("id_and_name"), column("a"), column("b")},
+		Indexes: []database.Index{
+			{Name: "PRIMARY", Columns: []string{"id"}, Primary: true, Unique: true},
+			{Name: "id_name", Columns: []string{"id", "name"}, Unique: true},
+			{Name: "id_and_name", Columns: []string{"id_and_name"}, Unique: true},
+			{Name: "a_b", Columns: []string{"a", "b"}, Unique: true},
+		},
+	}
+	// the struct of the table is named like the find function of the users primary key
+	find := &database.Table{
+		Name:    "find_users_by_id",
+		Type:    database.BaseTable,
+		Dialect: "mysql",
+		Columns: []database.Column{column("id")},
+	}
+	var warnings []string
+	o := Options{
+		Methods: []string{"sqlx"},
+		Naming:  Naming{Columns: map[string]string{"users.a": "IDS", "users.b": "Ids"}},
+		Warn:    func(message string) { warnings = append(warnings, message) },
+	}
+	src, err := Generate(o, File{Package: "p", Tables: []*database.Table{find, users}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+	for _, want := range []string{"func FindUsersByID2(", "func FindUsersByIDAndName(", "func FindUsersByIDAndName2(", "ids int64, ids2 int64"} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("the generated source is missing %q:\n%s", want, src)
+		}
+	}
+	if len(warnings) != 2 {
+		t.Errorf("expected a warning for each renamed key, got %q", warnings)
+	}
+}
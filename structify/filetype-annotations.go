@@ -1,10 +1,11 @@
 package structify
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/fatih/structtag"
-	"github.com/snagles/gostructify/cmd/gostructify/database"
+	"github.com/snagles/gostructify/database"
 )
 
 // JSONTags adds json specific annotations
@@ -17,6 +18,18 @@ func CSVTags(c database.Column) structtag.Tag {
 	return structtag.Tag{Key: "csv", Name: strings.ToLower(c.Name), Options: []string{"omitempty"}}
 }
 
+// ValidateTags adds validator annotations limiting strings and byte slices to the length of their column
+func ValidateTags(c database.Column, goType string) structtag.Tag {
+	if (goType != "string" && goType != "[]byte") || c.FullType() == c.DatabaseType || c.Length <= 0 {
+		return structtag.Tag{}
+	}
+	rules := []string{fmt.Sprintf("max=%d", c.Length)}
+	if c.DatabaseNullable == "YES" {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return structtag.Tag{Key: "validate", Name: strings.Join(rules, ","), Options: []string{}}
+}
+
 // XMLTags adds xml specific annotations
 func XMLTags(c database.Column) structtag.Tag {
 	return structtag.Tag{Key: "xml", Name: strings.ToLower(c.Name), Options: []string{"omitempty"}}
@@ -0,0 +1,68 @@
+package structify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/structtag"
+	"github.com/snagles/gostructify/database"
+)
+
+// GormTags adds gorm specific annotations
+func GormTags(c database.Column) structtag.Tag {
+	name := "column:" + c.Name
+	// sized types keep their size for migrations, ex: type:varchar(100)
+	if c.FullType() != c.DatabaseType {
+		name += ";type:" + c.FullType()
+	}
+	switch {
+	case c.PrimaryKey:
+		name += ";primaryKey"
+	case c.Unique:
+		name += ";unique"
+	}
+	if value, ok := literalDefault(c); ok {
+		name += ";default:" + value
+	}
+	return structtag.Tag{Key: "gorm", Name: name, Options: []string{}}
+}
+
+var numericLiteral = regexp.MustCompile(`^[-+]?[0-9]*\.?[0-9]+$`)
+
+// literalDefault returns the default of the column when it is a number, string or boolean literal.
+// Defaults calling functions such as now() or nextval() are left to the database.
+func literalDefault(c database.Column) (string, bool) {
+	if c.Default == nil {
+		return "", false
+	}
+	value := strings.TrimSpace(*c.Default)
+	// sql server wraps defaults in parentheses, ex: ((0))
+	for strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+	}
+	// postgres casts string defaults, ex: 'active'::character varying
+	if i := strings.LastIndex(value, "::"); i > 0 && strings.HasSuffix(value[:i], "'") {
+		value = value[:i]
+	}
+	switch {
+	case strings.ContainsAny(value, ";\"`"):
+		return "", false
+	case numericLiteral.MatchString(value):
+		return value, true
+	case len(value) > 1 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+		return value, true
+	case strings.EqualFold(value, "true"), strings.EqualFold(value, "false"):
+		return strings.ToLower(value), true
+	}
+	return "", false
+}
+
+// gormTemplate adds the table name method for gorm
+const gormTemplate = `
+{{- define "gorm"}}
+// TableName manually overrides gorms defaults of taking the struct name and pluralizing it
+// http://jinzhu.me/gorm/models.html#conventions
+func ({{receiver .Name}} *{{.Name}}) TableName() string {
+	return "{{.Table.QualifiedName}}"
+}
+{{end}}`
@@ -0,0 +1,182 @@
+package structify
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jinzhu/inflection"
+)
+
+// Naming are the rules converting table and column names to go identifiers
+type Naming struct {
+	// TrimPrefix and TrimSuffix are removed from table names, names that would be left empty are kept
+	TrimPrefix string
+	TrimSuffix string
+	// Singular singularizes table names, ex: users generates User
+	Singular bool
+	// QualifySchemas prefixes the struct names of the tables of the schemas with the schema name,
+	// * for every schema, ex: sales.orders generates SalesOrders
+	QualifySchemas []string
+	// Tables renames tables to the struct name, tables are named table or schema.table. Columns
+	// renames columns to the field name, columns are named schema.table.column or table.column for a
+	// column of one table, or column for the column of every table.
+	Tables  map[string]string
+	Columns map[string]string
+	// Initialisms are upper cased in addition to the golint initialisms such as ID, URL and HTTP
+	Initialisms []string
+	// NoInitialisms keeps the case of every word, ex: user_id generates UserId
+	NoInitialisms bool
+}
+
+// commonInitialisms are the initialisms of golint
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true,
+	"UID": true, "UUID": true, "URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// structName returns the struct name of the table of the schema, the renamed name or the table name
+// with the prefix and suffix trimmed, singularized, qualified and converted to an identifier
+func (n Naming) structName(schema, table string) string {
+	if name, ok := n.Tables[schema+"."+table]; ok && schema != "" {
+		return name
+	}
+	if name, ok := n.Tables[table]; ok {
+		return name
+	}
+	name := table
+	if trimmed := strings.TrimPrefix(name, n.TrimPrefix); trimmed != "" {
+		name = trimmed
+	}
+	if trimmed := strings.TrimSuffix(name, n.TrimSuffix); trimmed != "" {
+		name = trimmed
+	}
+	if n.Singular {
+		name = inflection.Singular(name)
+	}
+	if n.qualify(schema) {
+		name = schema + "_" + name
+	}
+	return n.identifier(name)
+}
+
+// qualify reports whether struct names of the tables of the schema are prefixed with the schema
+func (n Naming) qualify(schema string) bool {
+	if schema == "" {
+		return false
+	}
+	for _, s := range n.QualifySchemas {
+		if s == "*" || s == schema {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName returns the field name of the column of the table, named table or schema.table. Renames
+// of the column of the schema qualified table take precedence over renames of the column of the table
+// in every schema, which take precedence over renames of the column of every table.
+func (n Naming) fieldName(table, column string) string {
+	if name, ok := n.Columns[table+"."+column]; ok {
+		return name
+	}
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		if name, ok := n.Columns[table[i+1:]+"."+column]; ok {
+			return name
+		}
+	}
+	if name, ok := n.Columns[column]; ok {
+		return name
+	}
+	return n.identifier(column)
+}
+
+// identifier converts a name to an exported go identifier. Words are separated by any character
+// that is not a letter or digit and by lower to upper case changes, ex: user_id, user-id, "user id"
+// and userId all generate UserID. Names that do not start with an upper case letter once converted,
+// such as names starting with a digit, are prefixed with X.
+func (n Naming) identifier(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		if initialism, ok := n.initialism(w); ok {
+			b.WriteString(initialism)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(w)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(w[size:])
+	}
+	id := b.String()
+	if r, _ := utf8.DecodeRuneInString(id); !unicode.IsUpper(r) {
+		id = "X" + id
+	}
+	return id
+}
+
+// initialism returns the word upper cased when it is an initialism, plurals keep their lower case
+// s, ex: ids to IDs
+func (n Naming) initialism(word string) (string, bool) {
+	if n.NoInitialisms {
+		return "", false
+	}
+	upper := strings.ToUpper(word)
+	if n.isInitialism(upper) {
+		return upper, true
+	}
+	if l := len(upper); l > 2 && word[l-1] == 's' && n.isInitialism(upper[:l-1]) {
+		return upper[:l-1] + "s", true
+	}
+	return "", false
+}
+
+func (n Naming) isInitialism(upper string) bool {
+	if commonInitialisms[upper] {
+		return true
+	}
+	for _, i := range n.Initialisms {
+		if strings.ToUpper(i) == upper {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits the name into its words
+func words(name string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	var prev rune
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+		case unicode.IsUpper(r) && unicode.IsLower(prev) && len(word) > 0:
+			words = append(words, string(word))
+			word = []rune{r}
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// plural returns the plural of a struct name for has many fields of singularized structs
+func (n Naming) plural(name string) string {
+	if n.Singular {
+		return inflection.Plural(name)
+	}
+	return name
+}
@@ -0,0 +1,126 @@
+package structify
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/fatih/structtag"
+	"github.com/snagles/gostructify/database"
+)
+
+// relation is a belongs to or has many field linking two generated structs through a foreign key
+type relation struct {
+	name     string
+	goType   string
+	key      database.ForeignKey
+	owner    *database.Table // table holding the foreign key
+	referred *database.Table // table referenced by the foreign key
+}
+
+// relations returns the belongs to fields for the foreign keys of the table and the has many fields for
+// the foreign keys referencing it. Only tables of the same output file are related.
+func (o Options) relations(t *database.Table, tables []*database.Table) []relation {
+	taken := map[string]bool{}
+	for _, c := range t.Columns {
+		taken[o.Naming.fieldName(t.QualifiedName(), c.Name)] = true
+	}
+	var fields []relation
+	add := func(r relation, names ...string) {
+		for _, name := range names {
+			if !taken[name] {
+				r.name = name
+				break
+			}
+		}
+		if r.name == "" {
+			return
+		}
+		taken[r.name] = true
+		fields = append(fields, r)
+	}
+
+	// belongs to, ex: posts.author_id referencing users adds Author *Users to Posts
+	for _, key := range t.ForeignKeys {
+		referred := findTable(tables, referencedSchema(t, key), key.ReferencedTable)
+		if referred == nil {
+			continue
+		}
+		r := relation{goType: "*" + o.structName(referred), key: key, owner: t, referred: referred}
+		var names []string
+		if len(key.Columns) == 1 && strings.HasSuffix(strings.ToLower(key.Columns[0]), "_id") {
+			names = append(names, o.Naming.identifier(key.Columns[0][:len(key.Columns[0])-3]))
+		}
+		names = append(names, o.structName(referred), o.structName(referred)+"By"+o.keyFields(t, key.Columns, "And"))
+		add(r, names...)
+	}
+
+	// has many, ex: posts.author_id referencing users adds Posts []Posts to Users, or Posts []Post
+	// to User when table names are singularized
+	for _, owner := range tables {
+		for _, key := range owner.ForeignKeys {
+			if findTable(tables, referencedSchema(owner, key), key.ReferencedTable) != t {
+				continue
+			}
+			r := relation{goType: "[]" + o.structName(owner), key: key, owner: owner, referred: t}
+			name := o.Naming.plural(o.structName(owner))
+			add(r, name, name+"By"+o.keyFields(owner, key.Columns, "And"))
+		}
+	}
+	return fields
+}
+
+// relationTags returns the tags of the relation field, gorm is told which fields hold the key
+func (o Options) relationTags(r relation) string {
+	ts := &structtag.Tags{}
+	for _, option := range o.Tags {
+		switch option {
+		case "gorm":
+			references := r.key.ReferencedColumns
+			if len(references) == 0 {
+				references = r.referred.PrimaryKey()
+			}
+			name := "foreignKey:" + o.keyFields(r.owner, r.key.Columns, ",")
+			if len(references) > 0 {
+				name += ";references:" + o.keyFields(r.referred, references, ",")
+			}
+			ts.Set(&structtag.Tag{Key: "gorm", Name: name, Options: []string{}})
+		case "sqlx":
+			// relations are not columns of the table
+			ts.Set(&structtag.Tag{Key: "db", Name: "-", Options: []string{}})
+		}
+	}
+	sort.Sort(ts)
+	return ts.String()
+}
+
+// keyFields joins the field names of the key columns with the separator
+func (o Options) keyFields(t *database.Table, columns []string, separator string) string {
+	var names []string
+	for _, name := range columns {
+		for _, c := range t.Columns {
+			if c.Name == name {
+				names = append(names, o.Naming.fieldName(t.QualifiedName(), c.Name))
+			}
+		}
+	}
+	return strings.Join(names, separator)
+}
+
+// findTable returns the table of the schema and name, tables of the same name in other schemas are
+// different tables
+func findTable(tables []*database.Table, schema, name string) *database.Table {
+	for _, t := range tables {
+		if strings.EqualFold(t.Schema, schema) && strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// referencedSchema returns the schema of the table referenced by the foreign key of the table
+func referencedSchema(t *database.Table, key database.ForeignKey) string {
+	if key.ReferencedSchema != "" {
+		return key.ReferencedSchema
+	}
+	return t.Schema
+}
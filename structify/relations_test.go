@@ -0,0 +1,67 @@
+package structify
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/snagles/gostructify/database"
+)
+
+func TestRelationsAcrossSchemas(t *testing.T) {
+	column := func(name string) database.Column {
+		return database.Column{Name: name, DatabaseType: "integer", DatabaseNullable: "NO", Definition: database.ColumnDefinition{GoType: "int"}}
+	}
+	users := func(schema string) *database.Table {
+		return &database.Table{
+			Name:    "users",
+			Schema:  schema,
+			Type:    database.BaseTable,
+			Dialect: "postgresql",
+			Columns: []database.Column{column("id"), column("name")},
+			Indexes: []database.Index{{Name: "users_pkey", Columns: []string{"id"}, Primary: true, Unique: true}},
+		}
+	}
+	orders := func(schema string, key database.ForeignKey) *database.Table {
+		return &database.Table{
+			Name:        "orders",
+			Schema:      schema,
+			Type:        database.BaseTable,
+			Dialect:     "postgresql",
+			Columns:     []database.Column{column("id"), column("user_id")},
+			ForeignKeys: []database.ForeignKey{key},
+		}
+	}
+	o := Options{
+		Relations: true,
+		Naming: Naming{
+			QualifySchemas: []string{"*"},
+			Columns:        map[string]string{"sales.users.name": "SalesName", "users.name": "UserName"},
+		},
+	}
+	tables := []*database.Table{
+		users("public"),
+		users("sales"),
+		// the referenced table of the same schema
+		orders("sales", database.ForeignKey{Name: "orders_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}),
+		// the referenced table of another schema
+		orders("billing", database.ForeignKey{Name: "orders_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedSchema: "public", ReferencedColumns: []string{"id"}}),
+	}
+	src, err := Generate(o, File{Package: "p", Tables: tables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src, err = format.Source(src); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type PublicUsers struct {\n\tID            int\n\tUserName      int\n\tBillingOrders []BillingOrders\n}",
+		"type SalesUsers struct {\n\tID          int\n\tSalesName   int\n\tSalesOrders []SalesOrders\n}",
+		"type SalesOrders struct {\n\tID     int\n\tUserID int\n\tUser   *SalesUsers\n}",
+		"type BillingOrders struct {\n\tID     int\n\tUserID int\n\tUser   *PublicUsers\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("the generated source is missing\n%s\n%s", want, src)
+		}
+	}
+}
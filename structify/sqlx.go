@@ -0,0 +1,117 @@
+package structify
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/structtag"
+	"github.com/snagles/gostructify/database"
+)
+
+// SQLXTags adds sqlx specific annotations
+func SQLXTags(c database.Column) structtag.Tag {
+	t := structtag.Tag{Key: "db", Name: c.Name, Options: []string{}}
+	if c.PrimaryKey {
+		t.Options = append(t.Options, "pk")
+	}
+	return t
+}
+
+// sqlxTemplate adds an insert method binding the struct fields by their db tags, for tables only as
+// views are read only, and a function selecting a single row by the columns of the primary key and
+// of every unique index. The sql is written as a quoted go string as mysql quotes identifiers with
+// backticks.
+const sqlxTemplate = `
+{{- define "sqlx"}}
+{{- if not .Table.ReadOnly}}
+// Insert inserts the struct as a new row of {{.Table.QualifiedName}}
+func ({{receiver .Name}} *{{.Name}}) Insert(ctx context.Context, db sqlx.ExtContext) (sql.Result, error) {
+	return sqlx.NamedExecContext(ctx, db, {{printf "%q" (insertSQL .Table)}}, {{receiver .Name}})
+}
+{{end}}
+{{- range .Keys}}
+// Find{{$.Name}}By{{.Name}} selects a single {{$.Table.QualifiedName}} row using the {{.Index.Name}} index
+func Find{{$.Name}}By{{.Name}}(ctx context.Context, db sqlx.ExtContext, {{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f.Param}} {{$f.Column.Definition.GoType}}{{end}}) (*{{$.Name}}, error) {
+	var row {{$.Name}}
+	query := db.Rebind({{printf "%q" (findSQL $.Table .Fields)}})
+	if err := sqlx.GetContext(ctx, db, &row, query, {{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f.Param}}{{end}}); err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+{{end}}
+{{- end}}`
+
+// reservedParams are the names of the parameters, locals and packages of the find function that a
+// key parameter would shadow
+var reservedParams = map[string]bool{"ctx": true, "db": true, "row": true, "query": true, "err": true, "context": true, "sql": true, "sqlx": true}
+
+// paramName converts the field name to a parameter name by lower casing its leading upper case letters, ex: ID to id, UserID to userID.
+// Go keywords and reserved names get an underscore suffix, ex: type_, db_
+func paramName(field string) string {
+	runes := []rune(field)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) || (i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name := string(runes)
+	if token.IsKeyword(name) || reservedParams[name] {
+		name += "_"
+	}
+	return name
+}
+
+// insertSQL returns the named insert statement of the table. Auto increment and generated columns are
+// left out as the database fills them in.
+func insertSQL(t *database.Table) string {
+	var names, params []string
+	for _, c := range t.Columns {
+		if c.AutoIncrement || c.Generated {
+			continue
+		}
+		names = append(names, quoteIdentifier(t.Dialect, c.Name))
+		params = append(params, ":"+c.Name)
+	}
+	table := quoteTable(t)
+	if len(names) == 0 {
+		if t.Dialect == "mysql" || t.Dialect == "mariadb" {
+			return "INSERT INTO " + table + " () VALUES ()"
+		}
+		return "INSERT INTO " + table + " DEFAULT VALUES"
+	}
+	return "INSERT INTO " + table + " (" + strings.Join(names, ", ") + ") VALUES (" + strings.Join(params, ", ") + ")"
+}
+
+// findSQL returns the select statement of the table matching the columns of the fields, with ?
+// placeholders for sqlx to rebind
+func findSQL(t *database.Table, fields []Field) string {
+	var conditions []string
+	for _, f := range fields {
+		conditions = append(conditions, quoteIdentifier(t.Dialect, f.Column.Name)+" = ?")
+	}
+	return "SELECT * FROM " + quoteTable(t) + " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// quoteTable returns the quoted schema qualified name of the table
+func quoteTable(t *database.Table) string {
+	if t.Schema == "" {
+		return quoteIdentifier(t.Dialect, t.Name)
+	}
+	return quoteIdentifier(t.Dialect, t.Schema) + "." + quoteIdentifier(t.Dialect, t.Name)
+}
+
+// quoteIdentifier quotes the identifier for the dialect, backticks for mysql and mariadb, brackets for
+// sql server and double quotes otherwise
+func quoteIdentifier(dialect, name string) string {
+	switch dialect {
+	case "mysql", "mariadb":
+		return "`" + strings.Replace(name, "`", "``", -1) + "`"
+	case "mssql":
+		return "[" + strings.Replace(name, "]", "]]", -1) + "]"
+	default:
+		return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+	}
+}
@@ -0,0 +1,123 @@
+package structify
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/snagles/gostructify/database"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// sqlxStub declares the interfaces and functions of sqlx the generated methods use as sqlx declares
+// them, so the generated source type checks without the sqlx module
+const sqlxStub = `package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type Rows struct{ *sql.Rows }
+
+type Row struct{}
+
+type QueryerContext interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *Row
+}
+
+type ExecerContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type binder interface {
+	DriverName() string
+	Rebind(string) string
+	BindNamed(string, interface{}) (string, []interface{}, error)
+}
+
+type ExtContext interface {
+	binder
+	QueryerContext
+	ExecerContext
+}
+
+func NamedExecContext(ctx context.Context, e ExtContext, query string, arg interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func GetContext(ctx context.Context, q QueryerContext, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+`
+
+// importerFunc imports packages with a function
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// typeCheck parses and type checks the generated source with the imports goimports would add
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "gostructify.go", src, 0)
+	if err != nil {
+		t.Fatalf("parsing the generated source: %s\n%s", err, src)
+	}
+	for _, path := range []string{"context", "database/sql", "github.com/jmoiron/sqlx"} {
+		astutil.AddImport(fset, f, path)
+	}
+
+	std := importer.ForCompiler(fset, "source", nil)
+	imports := importerFunc(func(path string) (*types.Package, error) {
+		if path != "github.com/jmoiron/sqlx" {
+			return std.Import(path)
+		}
+		stub, err := parser.ParseFile(fset, "sqlx.go", sqlxStub, 0)
+		if err != nil {
+			return nil, err
+		}
+		return (&types.Config{Importer: std}).Check(path, fset, []*ast.File{stub}, nil)
+	})
+	var errs []string
+	conf := types.Config{Importer: imports, Error: func(err error) { errs = append(errs, err.Error()) }}
+	conf.Check("p", fset, []*ast.File{f}, nil)
+	if len(errs) > 0 {
+		t.Fatalf("the generated source does not compile:\n%s\n%s", strings.Join(errs, "\n"), src)
+	}
+}
+
+func TestSQLXReservedParams(t *testing.T) {
+	int64Column := func(name string) database.Column {
+		return database.Column{Name: name, DatabaseType: "bigint", DatabaseNullable: "NO", Definition: database.ColumnDefinition{GoType: "int64"}}
+	}
+	table := &database.Table{
+		Name:    "accounts",
+		Type:    database.BaseTable,
+		Dialect: "postgresql",
+		Columns: []database.Column{int64Column("db"), int64Column("ctx"), int64Column("row"), int64Column("query"), int64Column("sqlx"), int64Column("type")},
+		Indexes: []database.Index{
+			{Name: "accounts_pkey", Columns: []string{"db"}, Primary: true, Unique: true},
+			{Name: "accounts_key", Columns: []string{"ctx", "row", "query", "sqlx", "type"}, Unique: true},
+		},
+	}
+	src, err := Generate(Options{Methods: []string{"sqlx"}}, File{Package: "p", Tables: []*database.Table{table}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+}
+
+func TestParamName(t *testing.T) {
+	for field, want := range map[string]string{"ID": "id", "UserID": "userID", "Type": "type_", "Db": "db_", "Ctx": "ctx_", "Row": "row_", "Query": "query_"} {
+		if got := paramName(field); got != want {
+			t.Errorf("paramName(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package structify
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/structtag"
+	"github.com/snagles/gostructify/database"
+)
+
+// Options are the tags, methods, naming and templates of the generated structs
+type Options struct {
+	Tags         []string
+	Methods      []string
+	NullableType string
+	// Relations adds belongs to and has many fields for foreign keys between the tables
+	Relations bool
+	// Naming converts table and column names to struct and field names
+	Naming Naming
+	// Templates are template files parsed after the default templates, they can redefine the
+	// default templates or define the templates of new methods
+	Templates []string
+	// Warn is called with a message for every name renamed to resolve a collision, the messages are
+	// dropped when it is nil
+	Warn func(message string)
+	// keyNames are the names of the keys whose functions collide by key, set by resolveNames
+	keyNames map[string]string
+}
+
+// File is the data of the file template, the output file generated from the tables of a database
+type File struct {
+	Package  string
+	Database string
+	// Command is the command recorded in the code generated header, it should hold no secret
+	Command string
+	// Fingerprint identifies the tables in the code generated header, set by Generate
+	Fingerprint string
+	Tables      []*database.Table
+	// Imports are the packages goimports can not resolve on its own such as decimal types
+	Imports []string
+	Structs []Struct
+}
+
+// Struct is the data of the struct template and of the method templates
+type Struct struct {
+	Name     string
+	Database string
+	Table    *database.Table
+	Fields   []Field
+	// Relations are the belongs to and has many fields of the struct
+	Relations []Field
+	// Keys are the primary key and unique indexes of the table, without duplicate columns
+	Keys    []Key
+	Methods []string
+}
+
+// Field is a struct field, the column is empty for relation fields
+type Field struct {
+	Name   string
+	Type   string
+	Tags   string
+	Column database.Column
+	// Param is the parameter name of the field in the functions of a key, unique in the key
+	Param string
+}
+
+// Key is a primary key or unique index of a table with the fields of its columns
+type Key struct {
+	// Name joins the field names of the columns with And, ex: UserIDAndName
+	Name   string
+	Index  database.Index
+	Fields []Field
+}
+
+// Generate executes the file template for the tables of the file, the source is returned unformatted
+func Generate(o Options, f File) ([]byte, error) {
+	for _, tag := range o.Tags {
+		if !tagOptions[tag] {
+			return nil, fmt.Errorf("Unrecognized tag option: %s", tag)
+		}
+	}
+	// the sqlx methods bind the struct fields by their db tags
+	if hasString(o.Methods, "sqlx") && !hasString(o.Tags, "sqlx") {
+		o.Tags = append(o.Tags[:len(o.Tags):len(o.Tags)], "sqlx")
+	}
+	tmpl, err := o.templates()
+	if err != nil {
+		return nil, err
+	}
+	// the templates are parsed again so their functions use the resolved names
+	if o, err = o.resolveNames(tmpl, f.Database, f.Tables); err != nil {
+		return nil, err
+	}
+	if tmpl, err = o.templates(); err != nil {
+		return nil, err
+	}
+	f.Imports = Imports(f.Tables)
+	f.Fingerprint = database.Fingerprint(f.Tables)
+	f.Structs = nil
+	for _, t := range f.Tables {
+		f.Structs = append(f.Structs, o.newStruct(f.Database, t, f.Tables))
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "file", f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newStruct returns the struct data of the table, tables holds every table of the output file for
+// relationship fields
+func (o Options) newStruct(dbName string, t *database.Table, tables []*database.Table) Struct {
+	s := Struct{Name: o.structName(t), Database: dbName, Table: t, Methods: o.Methods}
+	// add each column and field based on passed parameters
+	for _, column := range t.Columns {
+		s.Fields = append(s.Fields, o.field(t.QualifiedName(), column))
+	}
+
+	// add belongs to and has many fields for foreign keys between the tables
+	if o.Relations {
+		for _, r := range o.relations(t, tables) {
+			s.Relations = append(s.Relations, Field{Name: r.name, Type: r.goType, Tags: o.relationTags(r)})
+		}
+	}
+
+	// keys of the primary key and every unique index
+	seen := map[string]bool{}
+	for _, index := range t.UniqueKeys() {
+		if columns := strings.Join(index.Columns, ","); !seen[columns] {
+			seen[columns] = true
+			key := Key{Index: index}
+			var names []string
+			params := map[string]string{}
+			for _, name := range index.Columns {
+				for _, c := range t.Columns {
+					if c.Name == name {
+						f := o.field(t.QualifiedName(), c)
+						// parameter names of different fields can be the same, ex: IDS and Ids
+						if f.Param = paramName(f.Name); params[f.Param] != "" {
+							f.Param = freeName(f.Param, params)
+						}
+						params[f.Param] = f.Name
+						key.Fields = append(key.Fields, f)
+						names = append(names, f.Name)
+					}
+				}
+			}
+			key.Name = strings.Join(names, "And")
+			if name, ok := o.keyNames[keyID(t, index)]; ok {
+				key.Name = name
+			}
+			s.Keys = append(s.Keys, key)
+		}
+	}
+	return s
+}
+
+func (o Options) field(table string, c database.Column) Field {
+	f := Field{Name: o.Naming.fieldName(table, c.Name), Type: fieldType(c, o.NullableType), Column: c}
+	if len(o.Tags) > 0 {
+		f.Tags = fieldTags(c, f.Type, o.Tags)
+	}
+	return f
+}
+
+// Imports returns the packages of the column types that goimports can not resolve on its own
+func Imports(tables []*database.Table) []string {
+	var packages []string
+	seen := map[string]bool{}
+	for _, t := range tables {
+		for _, c := range t.Columns {
+			if p := c.Definition.Import; p != "" && !seen[p] {
+				seen[p] = true
+				packages = append(packages, p)
+			}
+		}
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// structName returns the struct name of the table
+func (o Options) structName(t *database.Table) string {
+	return o.Naming.structName(t.Schema, t.Name)
+}
+
+func fieldType(c database.Column, nullabletype string) string {
+	// mariadb uses YES, vertica uses t/true
+	if c.DatabaseNullable == "YES" || c.DatabaseNullable == "t" || c.DatabaseNullable == "true" {
+		switch nullabletype {
+		case "guregu":
+			return c.Definition.GureguType
+		case "sql":
+			return c.Definition.SQLType
+		}
+	}
+	return c.Definition.GoType
+}
+
+// tagOptions are the recognized tag options
+var tagOptions = map[string]bool{"json": true, "xml": true, "gorm": true, "sqlx": true, "csv": true, "validate": true}
+
+func fieldTags(c database.Column, goType string, options []string) string {
+	// add things like xml, csv, json, gorm, sqlx tags
+	ts := &structtag.Tags{}
+	for _, o := range options {
+		var t structtag.Tag
+		switch o {
+		case "json":
+			t = JSONTags(c)
+		case "xml":
+			t = XMLTags(c)
+		case "gorm":
+			t = GormTags(c)
+		case "sqlx":
+			t = SQLXTags(c)
+		case "csv":
+			t = CSVTags(c)
+		case "validate":
+			// columns without a length have nothing to validate
+			if t = ValidateTags(c, goType); t.Key == "" {
+				continue
+			}
+		default:
+			// unrecognized options are reported by Generate
+			continue
+		}
+		ts.Set(&t)
+	}
+	// sort tags according to keys
+	sort.Sort(ts)
+	return fmt.Sprint(ts)
+}
+
+// hasString reports whether the list holds the value
+func hasString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package structify
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/snagles/gostructify/database"
+)
+
+// fileTemplate writes the code generated header, the package and imports, and every struct of the file.
+// The struct template writes the struct with its fields followed by the templates of its methods.
+const fileTemplate = `
+{{- define "file" -}}
+// Code generated by gostructify command "{{.Command}}"; DO NOT EDIT.
+// Schema fingerprint: {{.Fingerprint}}
+
+package {{.Package}}
+
+{{if .Imports}}import (
+{{range .Imports}}	{{printf "%q" .}}
+{{end}})
+
+{{end}}
+{{- range .Structs}}{{template "struct" .}}
+{{end}}
+{{- end}}
+
+{{- define "struct" -}}
+{{if .Table.ReadOnly -}}
+// {{.Name}} is the read only go struct representation of the {{lower .Table.Type}} {{with .Database}}{{.}}.{{end}}{{.Table.QualifiedName}}
+{{- else -}}
+// {{.Name}} is the go struct representation of {{with .Database}}{{.}}.{{end}}{{.Table.QualifiedName}}
+{{- end}}
+type {{.Name}} struct {
+{{range .Fields}}	{{template "field" .}}
+{{end}}
+{{- range .Relations}}	{{template "field" .}}
+{{end -}}
+}
+{{range .Methods}}{{method . $}}{{end}}
+{{- end}}
+
+{{- define "field"}}{{.Name}} {{.Type}}{{with .Tags}} ` + "`{{.}}`" + `{{end}}{{end}}
+`
+
+// templates parses the default templates followed by the template files of the options
+func (o Options) templates() (*template.Template, error) {
+	var tmpl *template.Template
+	funcs := template.FuncMap{
+		// method executes the template of a method option for the struct
+		"method": func(name string, s Struct) (string, error) {
+			if tmpl.Lookup(name) == nil {
+				return "", fmt.Errorf("Unrecognized method option: %s", name)
+			}
+			var buf bytes.Buffer
+			err := tmpl.ExecuteTemplate(&buf, name, s)
+			return buf.String(), err
+		},
+		"camel":      o.Naming.identifier,
+		"structName": func(name string) string { return o.Naming.structName("", name) },
+		"fieldName":  o.Naming.fieldName,
+		"goType":     func(c database.Column) string { return fieldType(c, o.NullableType) },
+		"tags":       func(c database.Column) string { return fieldTags(c, fieldType(c, o.NullableType), o.Tags) },
+		"receiver":   receiverName,
+		"param":      paramName,
+		"columns":    columnNames,
+		"insertSQL":  insertSQL,
+		"findSQL":    findSQL,
+		"join":       strings.Join,
+		"add":        func(a, b int) int { return a + b },
+		"lower":      func(v interface{}) string { return strings.ToLower(fmt.Sprint(v)) },
+		"upper":      func(v interface{}) string { return strings.ToUpper(fmt.Sprint(v)) },
+	}
+	tmpl = template.New("gostructify").Funcs(funcs)
+	for _, text := range []string{fileTemplate, gormTemplate, sqlxTemplate} {
+		if _, err := tmpl.Parse(text); err != nil {
+			return nil, err
+		}
+	}
+	if len(o.Templates) > 0 {
+		if _, err := tmpl.ParseFiles(o.Templates...); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// receiverName returns the receiver of the methods of the struct, its lower cased first letter
+func receiverName(structname string) string {
+	for _, r := range structname {
+		return string(unicode.ToLower(r))
+	}
+	return ""
+}
+
+// columnNames returns the column names of the table
+func columnNames(t *database.Table) []string {
+	var names []string
+	for _, c := range t.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
@@ -0,0 +1,93 @@
+package gostructify
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/snagles/gostructify/database"
+)
+
+// SelectTables resolves the table names and glob patterns for the database. Plain names are kept
+// in the order given while glob patterns such as * or audit_* are matched against the tables listed
+// by the database, and its views when views is set. Tables matching any exclude regular expression
+// are removed.
+func SelectTables(ctx context.Context, d database.Database, db string, patterns []string, excludes []string, views bool) ([]string, error) {
+	var expressions []*regexp.Regexp
+	for _, e := range excludes {
+		r, err := regexp.Compile(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude tables expression %s: %s", e, err)
+		}
+		expressions = append(expressions, r)
+	}
+
+	var all []string
+	var tables []string
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if !isPattern(pattern) {
+			if !seen[pattern] {
+				seen[pattern] = true
+				tables = append(tables, pattern)
+			}
+			continue
+		}
+		if all == nil {
+			var err error
+			if all, err = d.ListTables(ctx, db); err != nil {
+				return nil, fmt.Errorf("listing tables of %s: %w", db, err)
+			}
+			if views {
+				names, err := d.ListViews(ctx, db)
+				if err != nil {
+					return nil, fmt.Errorf("listing views of %s: %w", db, err)
+				}
+				all = append(all, names...)
+			}
+			sort.Strings(all)
+		}
+		for _, name := range all {
+			matched, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tables pattern %s: %s", pattern, err)
+			}
+			if matched && !seen[name] {
+				seen[name] = true
+				tables = append(tables, name)
+			}
+		}
+	}
+
+	var selected []string
+	for _, name := range tables {
+		if !excluded(name, expressions) {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no tables of %s match %s", db, strings.Join(patterns, ","))
+	}
+	return selected, nil
+}
+
+// isPattern reports whether the table name contains glob meta characters
+func isPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+func excluded(name string, expressions []*regexp.Regexp) bool {
+	for _, r := range expressions {
+		if r.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}